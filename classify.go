@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+// Class identifies the type of institution or transaction an RTN's prefix is
+// assigned to.
+type Class int
+
+const (
+	// ClassUnknown is returned alongside errors when an RTN can't be classified.
+	ClassUnknown Class = iota
+
+	// ClassGovernment identifies the United States government (prefix 00).
+	ClassGovernment
+
+	// ClassPrimary identifies primary institutions (prefixes 01-12).
+	ClassPrimary
+
+	// ClassThrift identifies thrift institutions (prefixes 21-32).
+	ClassThrift
+
+	// ClassElectronic identifies electronic transactions (prefixes 61-72).
+	ClassElectronic
+
+	// ClassTravelersCheck identifies traveler's checks (prefix 80).
+	ClassTravelersCheck
+
+	// ClassReserved identifies prefixes which are not assigned.
+	ClassReserved
+)
+
+// String returns the name of the class.
+func (c Class) String() string {
+	switch c {
+	case ClassGovernment:
+		return "government"
+	case ClassPrimary:
+		return "primary"
+	case ClassThrift:
+		return "thrift"
+	case ClassElectronic:
+		return "electronic"
+	case ClassTravelersCheck:
+		return "traveler's check"
+	case ClassReserved:
+		return "reserved"
+	}
+
+	return "unknown"
+}
+
+// prefixRanges is the table of assigned two-digit RTN prefixes. Prefixes not
+// covered by a range are reserved.
+var prefixRanges = []struct {
+	lo, hi int
+	class  Class
+}{
+	{0, 0, ClassGovernment},
+	{1, 12, ClassPrimary},
+	{21, 32, ClassThrift},
+	{61, 72, ClassElectronic},
+	{80, 80, ClassTravelersCheck},
+}
+
+// Classify returns the class assigned to the first two digits of the provided
+// RTN. The RTN must be 9 digits long, but its check digit is not verified;
+// callers which require a valid checksum should also call Validate. Invalid
+// characters are reported with an *InvalidCharacterError.
+func Classify(rtn string) (class Class, err error) {
+	if err = validateDigits(rtn); err != nil {
+		return ClassUnknown, err
+	}
+
+	return classifyPrefix(prefixOf(rtn)), nil
+}
+
+// ValidateStrict determines whether the provided RTN is valid as Validate does,
+// and additionally whether its first two digits fall within an assigned range
+// (00-12, 21-32, 61-72, or 80). RTNs with a reserved prefix produce
+// ErrUnassignedPrefix even if their checksum is correct. Invalid characters are
+// reported with an *InvalidCharacterError and checksum mismatches with a
+// *ChecksumError.
+func ValidateStrict(rtn string) (err error) {
+	if err = Validate(rtn); err != nil {
+		return err
+	}
+
+	if classifyPrefix(prefixOf(rtn)) == ClassReserved {
+		return ErrUnassignedPrefix
+	}
+
+	return nil
+}
+
+// classifyPrefix returns the class assigned to the provided two-digit prefix.
+func classifyPrefix(prefix int) Class {
+	for _, r := range prefixRanges {
+		if prefix >= r.lo && prefix <= r.hi {
+			return r.class
+		}
+	}
+
+	return ClassReserved
+}
+
+// validateDigits determines whether the provided RTN is made up of 9 digits,
+// without verifying its check digit. Invalid characters are reported with an
+// *InvalidCharacterError.
+func validateDigits(rtn string) (err error) {
+	if len(rtn) != 9 {
+		return ErrIncorrectLength
+	}
+
+	for i, r := range rtn {
+		if _, ok := runeToDigit(r); !ok {
+			return &InvalidCharacterError{Index: i, Rune: r}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedClass Class
+		expectedError error
+	}{
+		{"1234", ClassUnknown, ErrIncorrectLength},
+		{"0123456789", ClassUnknown, ErrIncorrectLength},
+		{"R00000000", ClassUnknown, ErrInvalidCharacter},
+		{"00000000X", ClassUnknown, ErrInvalidCharacter},
+		{"000000000", ClassGovernment, nil},
+		{"010000003", ClassPrimary, nil},
+		{"120000003", ClassPrimary, nil},
+		{"130000006", ClassReserved, nil},
+		{"200000004", ClassReserved, nil},
+		{"210000007", ClassThrift, nil},
+		{"320000007", ClassThrift, nil},
+		{"330000000", ClassReserved, nil},
+		{"600000002", ClassReserved, nil},
+		{"610000005", ClassElectronic, nil},
+		{"720000005", ClassElectronic, nil},
+		{"730000008", ClassReserved, nil},
+		{"790000006", ClassReserved, nil},
+		{"800000006", ClassTravelersCheck, nil},
+		{"810000009", ClassReserved, nil},
+		{"990000000", ClassReserved, nil},
+
+		// The checksum is not verified
+		{"123456789", ClassPrimary, nil},
+		{"590000005", ClassReserved, nil},
+	}
+
+	for _, test := range tests {
+		class, err := Classify(test.input)
+		if class != test.expectedClass || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				class,
+				err,
+				test.expectedClass,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestClassifyPrefix(t *testing.T) {
+	// Every prefix must be classified, and only the documented prefixes may be
+	// assigned
+	counts := make(map[Class]int)
+	for prefix := 0; prefix <= 99; prefix++ {
+		counts[classifyPrefix(prefix)]++
+	}
+
+	expected := map[Class]int{
+		ClassGovernment:     1,
+		ClassPrimary:        12,
+		ClassThrift:         12,
+		ClassElectronic:     12,
+		ClassTravelersCheck: 1,
+		ClassReserved:       62,
+	}
+	for class, count := range expected {
+		if counts[class] != count {
+			t.Fatalf("%d prefixes classified as \"%s\" (expected %d)", counts[class], class, count)
+		}
+	}
+}
+
+func TestValidateStrict(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected error
+	}{
+		{"1234", ErrIncorrectLength},
+		{"R00000000", ErrInvalidCharacter},
+		{"123456789", ErrChecksumMismatch},
+		{"990000007", ErrChecksumMismatch},
+		{"000000000", nil},
+		{"010000003", nil},
+		{"120000003", nil},
+		{"130000006", ErrUnassignedPrefix},
+		{"200000004", ErrUnassignedPrefix},
+		{"210000007", nil},
+		{"320000007", nil},
+		{"330000000", ErrUnassignedPrefix},
+		{"600000002", ErrUnassignedPrefix},
+		{"610000005", nil},
+		{"720000005", nil},
+		{"730000008", ErrUnassignedPrefix},
+		{"790000006", ErrUnassignedPrefix},
+		{"800000006", nil},
+		{"810000009", ErrUnassignedPrefix},
+		{"990000000", ErrUnassignedPrefix},
+		{"021200025", nil},
+	}
+
+	for _, test := range tests {
+		actual := ValidateStrict(test.input)
+		if !errors.Is(actual, test.expected) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%v\" (expected \"%v\")",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+
+		// Validate must continue to accept unassigned prefixes
+		if test.expected == ErrUnassignedPrefix && Validate(test.input) != nil {
+			t.Fatalf("input \"%s\" was rejected by Validate", test.input)
+		}
+	}
+}
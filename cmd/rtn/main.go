@@ -0,0 +1,378 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+// Command rtn validates, completes, and looks up ABA routing transit numbers.
+//
+// Usage:
+//
+//	rtn validate [-json] [-redact <policy>] [-scheme <name>] <rtn | ->
+//	rtn validate [-json] [-redact <policy>] -file <path> [-format <format>]
+//	rtn complete [-json] <rtn with one digit replaced by X>
+//	rtn check-digit [-json] <first 8 digits>
+//	rtn lookup [-json] [-redact <policy>] -fedach-dir <FedACHdir.txt> <rtn>
+//
+// Passing "-" to validate reads newline-separated RTNs from standard input.
+// The -scheme flag selects the routing number scheme used by validate and
+// defaults to "aba". Passing -file validates the RTNs in a file of plain lines,
+// CSV, TSV, JSONL, or fixed-width records, detecting the format unless -format
+// names it, and reports only those which are invalid.
+//
+// The -redact flag redacts the RTNs and errors written by validate and lookup
+// under the "default" policy, rtnutil.DefaultRedactionPolicy, or the "full"
+// policy, which writes no digits of RTNs or errors. It defaults to "none".
+//
+// The exit status is 0 on success, 1 if an RTN is invalid or not found, and 2
+// if the command is used incorrectly.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/schultz-is/rtnutil"
+)
+
+// Exit statuses.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// usage describes the available subcommands.
+const usage = `usage:
+  rtn validate [-json] [-redact <policy>] [-scheme <name>] <rtn | ->
+  rtn validate [-json] [-redact <policy>] -file <path> [-format <format>]
+  rtn complete [-json] <rtn with one digit replaced by X>
+  rtn check-digit [-json] <first 8 digits>
+  rtn lookup [-json] [-redact <policy>] -fedach-dir <FedACHdir.txt> <rtn>
+`
+
+// schemes holds the routing number schemes selectable by name with -scheme.
+var schemes = map[string]rtnutil.Scheme{
+	"aba": rtnutil.ABA(),
+}
+
+// redactionPolicies holds the redaction policies selectable by name with
+// -redact. A nil policy leaves output unredacted.
+var redactionPolicies = map[string]*rtnutil.RedactionPolicy{
+	"none":    nil,
+	"default": &rtnutil.DefaultRedactionPolicy,
+	"full":    {FullRedact: true},
+}
+
+// errUsage indicates that a subcommand was used incorrectly.
+var errUsage = errors.New("incorrect usage")
+
+// command holds the streams and flags shared by every subcommand.
+type command struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	json   bool
+	dir    string
+	scheme string
+	file   string
+	format string
+	redact string
+	policy *rtnutil.RedactionPolicy
+}
+
+// result is the JSON representation of the outcome of a single RTN.
+type result struct {
+	Line       int    `json:"line,omitempty"`
+	RTN        string `json:"rtn"`
+	Valid      bool   `json:"valid"`
+	Digit      *int   `json:"digit,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Code       string `json:"code,omitempty"`
+	Name       string `json:"name,omitempty"`
+	City       string `json:"city,omitempty"`
+	State      string `json:"state,omitempty"`
+	HeadOffice *bool  `json:"head_office,omitempty"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand named by the first argument and returns the exit
+// status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	var (
+		c     = &command{stdin: stdin, stdout: stdout, stderr: stderr, redact: "none"}
+		flags = flag.NewFlagSet("rtn "+args[0], flag.ContinueOnError)
+		sub   func(args []string) (int, error)
+	)
+
+	flags.SetOutput(stderr)
+	flags.BoolVar(&c.json, "json", false, "write results as JSON")
+
+	switch args[0] {
+	case "validate":
+		flags.StringVar(&c.scheme, "scheme", "aba", "routing number scheme to validate under")
+		flags.StringVar(&c.file, "file", "", "path to a file of RTNs to validate")
+		flags.StringVar(&c.format, "format", "", "format of the file (lines, csv, tsv, jsonl, or fixed-width)")
+		flags.StringVar(&c.redact, "redact", "none", "redaction policy for output (none, default, or full)")
+		sub = c.validate
+	case "complete":
+		sub = c.complete
+	case "check-digit":
+		sub = c.checkDigit
+	case "lookup":
+		flags.StringVar(&c.dir, "fedach-dir", "", "path to the FedACH participant directory")
+		flags.StringVar(&c.redact, "redact", "none", "redaction policy for output (none, default, or full)")
+		sub = c.lookup
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "rtn: unknown command %q\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	policy, ok := redactionPolicies[c.redact]
+	if !ok {
+		fmt.Fprintf(stderr, "rtn: unknown redaction policy %q\n%s", c.redact, usage)
+		return exitUsage
+	}
+	c.policy = policy
+
+	status, err := sub(flags.Args())
+	if errors.Is(err, errUsage) {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	if err != nil {
+		if c.policy != nil {
+			err = c.policy.RedactError("", err)
+		}
+		fmt.Fprintf(stderr, "rtn: %s\n", err)
+		return exitFailure
+	}
+
+	return status
+}
+
+// validate validates a single RTN, each line of standard input, or a file.
+func (c *command) validate(args []string) (status int, err error) {
+	if c.file != "" {
+		return c.validateFile(args)
+	}
+	if len(args) != 1 || c.format != "" {
+		return exitUsage, errUsage
+	}
+
+	scheme, ok := schemes[c.scheme]
+	if !ok {
+		fmt.Fprintf(c.stderr, "rtn: unknown scheme %q\n", c.scheme)
+		return exitUsage, errUsage
+	}
+
+	if args[0] != "-" {
+		// The argument is validated as a single line so that both paths
+		// validate under the scheme in the same way
+		_, err = rtnutil.ValidateLines(
+			strings.NewReader(args[0]+"\n"),
+			func(_ int, _ string, err error) bool {
+				status = c.report(result{RTN: args[0]}, err)
+				return false
+			},
+			rtnutil.LooseLines(),
+			rtnutil.WithLineScheme(scheme),
+		)
+
+		return status, err
+	}
+
+	status = exitOK
+	_, err = rtnutil.ValidateLines(
+		c.stdin,
+		func(line int, rtn string, err error) bool {
+			if c.report(result{Line: line, RTN: rtn}, err) != exitOK {
+				status = exitFailure
+			}
+			return true
+		},
+		rtnutil.SkipBlankLines(),
+		rtnutil.LooseLines(),
+		rtnutil.WithLineScheme(scheme),
+	)
+
+	return status, err
+}
+
+// validateFile validates the RTNs in the file named by -file.
+func (c *command) validateFile(args []string) (status int, err error) {
+	if len(args) != 0 || c.scheme != "aba" {
+		return exitUsage, errUsage
+	}
+
+	// An empty format leaves the format to be detected
+	var format rtnutil.FileFormat
+	for candidate := rtnutil.FileFormatLines; candidate <= rtnutil.FileFormatFixedWidth; candidate++ {
+		if candidate.String() == c.format {
+			format = candidate
+		}
+	}
+	if c.format != "" && format == rtnutil.FileFormatUnknown {
+		fmt.Fprintf(c.stderr, "rtn: unknown format %q\n", c.format)
+		return exitUsage, errUsage
+	}
+
+	f, err := os.Open(c.file)
+	if err != nil {
+		return exitFailure, err
+	}
+	defer f.Close()
+
+	_, issues, err := rtnutil.ValidateFile(f, rtnutil.WithFileFormat(format))
+
+	status = exitOK
+	for _, issue := range issues {
+		status = c.report(result{Line: issue.Record, RTN: issue.Value}, issue.Err)
+	}
+
+	return status, err
+}
+
+// complete calculates the missing digit of an RTN.
+func (c *command) complete(args []string) (status int, err error) {
+	if len(args) != 1 {
+		return exitUsage, errUsage
+	}
+
+	digit, err := rtnutil.GetMissingDigit(strings.ToUpper(args[0]))
+	if err != nil {
+		return c.report(result{RTN: args[0]}, err), nil
+	}
+
+	rtn := strings.Replace(strings.ToUpper(args[0]), "X", fmt.Sprint(digit), 1)
+	if c.json {
+		return c.report(result{RTN: rtn, Digit: &digit}, nil), nil
+	}
+
+	fmt.Fprintf(c.stdout, "%d %s\n", digit, rtn)
+	return exitOK, nil
+}
+
+// checkDigit calculates the check digit for the first 8 digits of an RTN.
+func (c *command) checkDigit(args []string) (status int, err error) {
+	if len(args) != 1 {
+		return exitUsage, errUsage
+	}
+
+	digit, err := rtnutil.ComputeCheckDigit(args[0])
+	if err != nil {
+		return c.report(result{RTN: args[0]}, err), nil
+	}
+
+	if c.json {
+		rtn, _ := rtnutil.Complete(args[0])
+		return c.report(result{RTN: rtn, Digit: &digit}, nil), nil
+	}
+
+	fmt.Fprintln(c.stdout, digit)
+	return exitOK, nil
+}
+
+// lookup finds an RTN in the FedACH participant directory.
+func (c *command) lookup(args []string) (status int, err error) {
+	if len(args) != 1 || c.dir == "" {
+		return exitUsage, errUsage
+	}
+
+	rtn, err := rtnutil.Normalize(args[0])
+	if err == nil {
+		err = rtnutil.Validate(rtn)
+	}
+	if err != nil {
+		return c.report(result{RTN: args[0]}, err), nil
+	}
+
+	f, err := os.Open(c.dir)
+	if err != nil {
+		return exitFailure, err
+	}
+	defer f.Close()
+
+	// Malformed records elsewhere in the directory shouldn't prevent lookups
+	participants, err := rtnutil.ParseFedACHDirectory(f, rtnutil.CollectErrors())
+	var recordErrs rtnutil.RecordErrors
+	if err != nil && !errors.As(err, &recordErrs) {
+		return exitFailure, err
+	}
+
+	p, ok := rtnutil.NewDirectory(participants).Lookup(rtn)
+	if !ok {
+		return c.report(result{RTN: rtn}, rtnutil.ErrParticipantNotFound), nil
+	}
+
+	if c.json {
+		return c.report(result{
+			RTN:        rtn,
+			Name:       p.CustomerName,
+			City:       p.City,
+			State:      p.State,
+			HeadOffice: &p.HeadOffice,
+		}, nil), nil
+	}
+
+	fmt.Fprintf(c.stdout, "%s\n%s, %s\n", p.CustomerName, p.City, p.State)
+	return exitOK, nil
+}
+
+// report writes the outcome of a single RTN and returns the corresponding exit
+// status. Failures are written to standard error unless JSON output is
+// requested, in which case every outcome is written to standard output. The
+// RTN and error are redacted under the -redact policy, if any.
+func (c *command) report(r result, err error) (status int) {
+	if c.policy != nil {
+		r.RTN, err = c.policy.Redact(r.RTN), c.policy.RedactError("", err)
+	}
+
+	status = exitOK
+	r.Valid = err == nil
+	if err != nil {
+		status = exitFailure
+		r.Error = err.Error()
+		if code := rtnutil.ErrorCode(err); code != rtnutil.CodeUnknown {
+			r.Code = code
+		}
+	}
+
+	if c.json {
+		// Encoding a result cannot fail
+		data, _ := json.Marshal(r)
+		fmt.Fprintf(c.stdout, "%s\n", data)
+		return status
+	}
+
+	var prefix = r.RTN
+	if r.Line > 0 {
+		prefix = fmt.Sprintf("line %d: %s", r.Line, r.RTN)
+	}
+
+	if err != nil {
+		fmt.Fprintf(c.stderr, "%s: %s\n", prefix, err)
+	} else {
+		fmt.Fprintf(c.stdout, "%s: valid\n", prefix)
+	}
+
+	return status
+}
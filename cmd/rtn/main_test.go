@@ -0,0 +1,216 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/schultz-is/rtnutil"
+)
+
+// sumScheme is a non-ABA scheme used to test -scheme. Its routing numbers are
+// five digits, the last of which is the sum of the others modulo 10.
+type sumScheme struct{}
+
+func (sumScheme) Length() int {
+	return 5
+}
+
+func (s sumScheme) Validate(v string) error {
+	if len(v) != 5 {
+		return rtnutil.ErrIncorrectLength
+	}
+
+	complete, err := s.Complete(v[:4])
+	if err != nil {
+		return err
+	}
+	if complete != v {
+		return rtnutil.ErrChecksumMismatch
+	}
+
+	return nil
+}
+
+func (sumScheme) Complete(prefix string) (string, error) {
+	if len(prefix) != 4 {
+		return "", rtnutil.ErrIncorrectLength
+	}
+
+	var sum int
+	for _, r := range prefix {
+		if r < '0' || r > '9' {
+			return "", rtnutil.ErrInvalidCharacter
+		}
+		sum += int(r - '0')
+	}
+
+	return prefix + string(rune('0'+sum%10)), nil
+}
+
+func (s sumScheme) Format(v string) (string, error) {
+	if err := s.Validate(v); err != nil {
+		return "", err
+	}
+
+	return v, nil
+}
+
+func TestRun(t *testing.T) {
+	const directory = "../../testdata/FedACHdir.txt"
+
+	schemes["sum"] = sumScheme{}
+	defer delete(schemes, "sum")
+
+	tests := []struct {
+		args           []string
+		stdin          string
+		expectedStatus int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{[]string{"validate", "021200025"}, "", 0, "021200025: valid\n", ""},
+		{[]string{"validate", "0212-0002-5"}, "", 0, "0212-0002-5: valid\n", ""},
+		{[]string{"validate", "123456789"}, "", 1, "", "123456789: checksum mismatch: checksum remainder 9\n"},
+		{[]string{"validate", "12345678"}, "", 1, "", "12345678: incorrect length\n"},
+		{
+			[]string{"validate", "-json", "123456789"}, "", 1,
+			`{"rtn":"123456789","valid":false,"error":"checksum mismatch: checksum remainder 9","code":"checksum_mismatch"}` + "\n",
+			"",
+		},
+		{
+			[]string{"validate", "-"}, "021200025\n\n123456789\r\n322286188\n", 1,
+			"line 1: 021200025: valid\nline 4: 322286188: valid\n",
+			"line 3: 123456789: checksum mismatch: checksum remainder 9\n",
+		},
+		{[]string{"validate", "-"}, "0212-0002-5\n", 0, "line 1: 0212-0002-5: valid\n", ""},
+		{[]string{"validate", "-"}, "0212 0002 5\n", 0, "line 1: 0212 0002 5: valid\n", ""},
+		{
+			[]string{"validate", "--json", "-"}, "021200025\n", 0,
+			`{"line":1,"rtn":"021200025","valid":true}` + "\n",
+			"",
+		},
+		{[]string{"validate", "-scheme", "aba", "021200025"}, "", 0, "021200025: valid\n", ""},
+		{[]string{"validate", "-scheme", "sum", "1234-0"}, "", 0, "1234-0: valid\n", ""},
+		{[]string{"validate", "-scheme", "sum", "021200025"}, "", 1, "", "021200025: incorrect length\n"},
+		{
+			[]string{"validate", "-scheme", "sum", "-"}, "12340\n12341\n", 1,
+			"line 1: 12340: valid\n",
+			"line 2: 12341: checksum mismatch\n",
+		},
+		{[]string{"validate", "-scheme", "iban", "021200025"}, "", 2, "", "rtn: unknown scheme \"iban\"\n" + usage},
+		{[]string{"validate", ""}, "", 1, "", ": incorrect length\n"},
+		{[]string{"validate", "-file", "../../testdata/rtns.csv"}, "", 1, "", "line 3: 0212-0002-6: checksum mismatch: checksum remainder 1\n"},
+		{
+			[]string{"validate", "-json", "-file", "../../testdata/rtns.csv", "-format", "csv"}, "", 1,
+			`{"line":3,"rtn":"0212-0002-6","valid":false,"error":"checksum mismatch: checksum remainder 1","code":"checksum_mismatch"}` + "\n",
+			"",
+		},
+		{
+			[]string{"validate", "-file", "../../testdata/rtns.csv", "-format", "lines"}, "", 1, "",
+			"line 1: name,routing: invalid character 'n' at index 0\n" +
+				"line 2: Alice,021200025: invalid character 'A' at index 0\n" +
+				"line 3: Bob,0212-0002-6: invalid character 'B' at index 0\n" +
+				"line 4: Carol,322286188: invalid character 'C' at index 0\n" +
+				"line 5: Dave,111000025: invalid character 'D' at index 0\n",
+		},
+		{[]string{"validate", "-file", directory}, "", 1, "", "rtn: ambiguous rtn column\n"},
+		{[]string{"validate", "-file", "missing.csv"}, "", 1, "", "rtn: open missing.csv: no such file or directory\n"},
+		{[]string{"validate", "-file", directory, "-format", "xml"}, "", 2, "", "rtn: unknown format \"xml\"\n" + usage},
+		{[]string{"validate", "-file", directory, "021200025"}, "", 2, "", usage},
+		{[]string{"validate", "-format", "csv", "021200025"}, "", 2, "", usage},
+		{[]string{"validate", "-redact", "default", "123456789"}, "", 1, "", "1234***89: checksum mismatch: checksum remainder 9\n"},
+		{[]string{"validate", "-redact", "full", "123456789"}, "", 1, "", "*********: checksum mismatch: checksum remainder *\n"},
+		{[]string{"validate", "-redact", "some", "123456789"}, "", 2, "", "rtn: unknown redaction policy \"some\"\n" + usage},
+		{[]string{"complete", "3222861X8"}, "", 0, "8 322286188\n", ""},
+		{[]string{"complete", "3222861x8"}, "", 0, "8 322286188\n", ""},
+		{[]string{"complete", "-json", "X22286188"}, "", 0, `{"rtn":"322286188","valid":true,"digit":3}` + "\n", ""},
+		{[]string{"complete", "XX2286188"}, "", 1, "", "XX2286188: too many missing digits\n"},
+		{[]string{"check-digit", "02120002"}, "", 0, "5\n", ""},
+		{[]string{"check-digit", "-json", "02120002"}, "", 0, `{"rtn":"021200025","valid":true,"digit":5}` + "\n", ""},
+		{[]string{"check-digit", "0212000"}, "", 1, "", "0212000: incorrect length\n"},
+		{
+			[]string{"lookup", "-fedach-dir", directory, "026014601"}, "", 0,
+			"EXAMPLE NATIONAL BANK\nNEW YORK, NY\n",
+			"",
+		},
+		{
+			[]string{"lookup", "-json", "-fedach-dir", directory, "026014601"}, "", 0,
+			`{"rtn":"026014601","valid":true,"name":"EXAMPLE NATIONAL BANK","city":"NEW YORK","state":"NY","head_office":false}` + "\n",
+			"",
+		},
+		{[]string{"lookup", "-fedach-dir", directory, "044000037"}, "", 1, "", "044000037: participant not found\n"},
+		{
+			[]string{"lookup", "-json", "-fedach-dir", directory, "0440-0003-7"}, "", 1,
+			`{"rtn":"044000037","valid":false,"error":"participant not found","code":"participant_not_found"}` + "\n",
+			"",
+		},
+		{[]string{"lookup", "-fedach-dir", directory, "123456789"}, "", 1, "", "123456789: checksum mismatch: checksum remainder 9\n"},
+		{[]string{"lookup", "-fedach-dir", "missing.txt", "026014601"}, "", 1, "", "rtn: open missing.txt: no such file or directory\n"},
+		{[]string{"lookup", "026014601"}, "", 2, "", usage},
+		{[]string{"validate"}, "", 2, "", usage},
+		{
+			[]string{"validate", "-fedach-dir", directory, "021200025"}, "", 2, "",
+			"flag provided but not defined: -fedach-dir\nUsage of rtn validate:\n" +
+				"  -file string\n    \tpath to a file of RTNs to validate\n" +
+				"  -format string\n    \tformat of the file (lines, csv, tsv, jsonl, or fixed-width)\n" +
+				"  -json\n    \twrite results as JSON\n" +
+				"  -redact string\n    \tredaction policy for output (none, default, or full) (default \"none\")\n" +
+				"  -scheme string\n    \trouting number scheme to validate under (default \"aba\")\n",
+		},
+		{[]string{"frobnicate"}, "", 2, "", "rtn: unknown command \"frobnicate\"\n" + usage},
+		{[]string{}, "", 2, "", usage},
+		{[]string{"help"}, "", 0, usage, ""},
+	}
+
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+
+		status := run(test.args, strings.NewReader(test.stdin), &stdout, &stderr)
+		if status != test.expectedStatus ||
+			stdout.String() != test.expectedStdout ||
+			stderr.String() != test.expectedStderr {
+			t.Fatalf(
+				"args %q generated actual output %d, %q, %q (expected %d, %q, %q)",
+				test.args,
+				status,
+				stdout.String(),
+				stderr.String(),
+				test.expectedStatus,
+				test.expectedStdout,
+				test.expectedStderr,
+			)
+		}
+	}
+}
+
+func TestRunFullRedaction(t *testing.T) {
+	const directory = "../../testdata/FedACHdir.txt"
+
+	tests := [][]string{
+		{"validate", "021200025"},
+		{"validate", "123456789"},
+		{"validate", "02120O025"},
+		{"validate", "-json", "021200025"},
+		{"validate", "-json", "123456789"},
+		{"validate", "-file", directory, "-format", "jsonl"},
+		{"validate", "-file", directory},
+		{"lookup", "-fedach-dir", directory, "044000037"},
+		{"lookup", "-fedach-dir", directory, "123456789"},
+		{"lookup", "-json", "-fedach-dir", directory, "026014601"},
+	}
+
+	for _, args := range tests {
+		var stdout, stderr bytes.Buffer
+
+		args = append([]string{args[0], "-redact", "full"}, args[1:]...)
+		run(args, strings.NewReader(""), &stdout, &stderr)
+		if output := stdout.String() + stderr.String(); strings.ContainsAny(output, "0123456789") {
+			t.Fatalf("args %q generated output containing digits %q", args, output)
+		}
+	}
+}
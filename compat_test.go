@@ -0,0 +1,219 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var updateAPI = flag.Bool("update-api", false, "record the current exported API as the compatibility baseline")
+
+// apiPackages lists the directories whose exported API is held compatible,
+// along with the baseline file recording each.
+var apiPackages = []struct {
+	dir      string
+	baseline string
+}{
+	{".", "testdata/api/rtnutil.txt"},
+	{"rtnhttp", "testdata/api/rtnhttp.txt"},
+}
+
+// minGoMinor is the minor version of the go directive in go.mod.
+const minGoMinor = 20
+
+// apiContext selects files as the oldest Go version supported by the module
+// does, so that the recorded API doesn't depend on the toolchain running the
+// test. Files limited to newer versions, such as slog.go, are not recorded.
+func apiContext() build.Context {
+	ctx := build.Default
+	ctx.ReleaseTags = nil
+	for i := 1; i <= minGoMinor; i++ {
+		ctx.ReleaseTags = append(ctx.ReleaseTags, fmt.Sprintf("go1.%d", i))
+	}
+
+	return ctx
+}
+
+// TestAPICompatibility fails if any exported declaration recorded in the
+// baseline has been removed or has changed, including the messages of
+// exported error values. New declarations are permitted; run the test with
+// -update-api to record them.
+func TestAPICompatibility(t *testing.T) {
+	for _, pkg := range apiPackages {
+		current := exportedAPI(t, pkg.dir)
+
+		if *updateAPI {
+			data := strings.Join(current, "\n") + "\n"
+			if err := os.WriteFile(pkg.baseline, []byte(data), 0o644); err != nil {
+				t.Fatalf("writing baseline failed: %s", err)
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(pkg.baseline)
+		if err != nil {
+			t.Fatalf("reading baseline failed: %s", err)
+		}
+
+		present := make(map[string]bool, len(current))
+		for _, line := range current {
+			present[line] = true
+		}
+
+		var added int
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if !present[line] {
+				t.Errorf("%s: incompatible change to recorded API: %s", pkg.dir, line)
+			}
+			delete(present, line)
+		}
+		added = len(present)
+
+		if added > 0 {
+			t.Logf("%s: %d exported declarations are not yet recorded; run with -update-api", pkg.dir, added)
+		}
+	}
+}
+
+// exportedAPI type-checks the package in the provided directory and describes
+// each of its exported declarations on a single line.
+func exportedAPI(t *testing.T, dir string) (lines []string) {
+	t.Helper()
+
+	var (
+		ctx  = apiContext()
+		fset = token.NewFileSet()
+	)
+	pkgs, err := parser.ParseDir(
+		fset,
+		dir,
+		func(fi os.FileInfo) bool {
+			if strings.HasSuffix(fi.Name(), "_test.go") {
+				return false
+			}
+			match, err := ctx.MatchFile(dir, fi.Name())
+			return err == nil && match
+		},
+		0,
+	)
+	if err != nil {
+		t.Fatalf("parsing %s failed: %s", dir, err)
+	}
+
+	var (
+		files     []*ast.File
+		errValues = make(map[string]string)
+	)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			files = append(files, file)
+			collectErrorValues(file, errValues)
+		}
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(filepath.Base(dir), fset, files, nil)
+	if err != nil {
+		t.Fatalf("type-checking %s failed: %s", dir, err)
+	}
+
+	qualifier := types.RelativeTo(pkg)
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+
+		line := types.ObjectString(obj, qualifier)
+		if c, ok := obj.(*types.Const); ok {
+			line += " = " + c.Val().ExactString()
+		}
+		if msg, ok := errValues[name]; ok {
+			line += " = errors.New(" + strconv.Quote(msg) + ")"
+		}
+		lines = append(lines, line)
+
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			continue
+		}
+
+		// Describe the exported methods and fields of named types
+		named, ok := typeName.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+		for i := 0; i < named.NumMethods(); i++ {
+			if named.Method(i).Exported() {
+				lines = append(lines, types.ObjectString(named.Method(i), qualifier))
+			}
+		}
+
+		if st, ok := typeName.Type().Underlying().(*types.Struct); ok {
+			for i := 0; i < st.NumFields(); i++ {
+				if st.Field(i).Exported() {
+					lines = append(lines, "field "+name+"."+st.Field(i).Name()+" "+types.TypeString(st.Field(i).Type(), qualifier))
+				}
+			}
+		}
+	}
+
+	sort.Strings(lines)
+	return lines
+}
+
+// collectErrorValues records the messages of package-level variables
+// initialized with errors.New.
+func collectErrorValues(file *ast.File, values map[string]string) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) {
+					continue
+				}
+
+				call, ok := vs.Values[i].(*ast.CallExpr)
+				if !ok || len(call.Args) != 1 {
+					continue
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "New" {
+					continue
+				}
+				if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != "errors" {
+					continue
+				}
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+
+				if msg, err := strconv.Unquote(lit.Value); err == nil {
+					values[name.Name] = msg
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"crypto/subtle"
+)
+
+// ValidateConstantTime determines whether a provided RTN is in valid MICR
+// format with a correct check digit, as Validate does. Failures are reported
+// with the bare sentinel errors, since the position carried by the detailed
+// errors of Validate is what it must not reveal. Unlike Validate, it always processes all nine positions and does not branch
+// on the values of the digits, so the time taken does not reveal where an
+// invalid character or incorrect digit appears. It is slightly slower than
+// Validate and should be used when RTN validation gates an endpoint which
+// could otherwise be used for enumeration. Only the length of the input is
+// checked before processing.
+func ValidateConstantTime(rtn string) (err error) {
+	// MICR RTNs are 9 digits
+	if len(rtn) != 9 {
+		return ErrIncorrectLength
+	}
+
+	var (
+		i        int
+		c        int
+		isDigit  int
+		invalid  int
+		checksum int
+	)
+
+	// Iterate over each byte in the string
+	for i = 0; i < 9; i++ {
+		c = int(rtn[i])
+
+		// Determine whether the byte is a digit without branching on its value
+		isDigit = subtle.ConstantTimeLessOrEq('0', c) & subtle.ConstantTimeLessOrEq(c, '9')
+		invalid |= isDigit ^ 1
+
+		// Multiply the digit by its respective multiplier and add to the
+		// checksum, contributing nothing if the byte isn't a digit
+		checksum += (c - '0') * isDigit * checksumMultipliers[i%3]
+	}
+
+	if invalid != 0 {
+		return ErrInvalidCharacter
+	}
+
+	// If the checksum is not evenly divisible by 10, the RTN is invalid
+	if checksum%10 != 0 {
+		return ErrChecksumMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestValidateConstantTime(t *testing.T) {
+	corpus := []string{
+		"", "asdf", "1234", "0123456789", "R00000000", "00000000R",
+		"123456789", "322286188", "021200025", "111000025", "026014601",
+		"0212000２5", "02120002/", "02120002:", "\x0021200025"[:9],
+	}
+
+	// Every single-digit substitution of a valid RTN
+	for i := 0; i < 9; i++ {
+		for c := byte(0); c < 128; c++ {
+			b := []byte("322286188")
+			b[i] = c
+			corpus = append(corpus, string(b))
+		}
+	}
+
+	// Random strings of digits and arbitrary bytes
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		b := make([]byte, 9)
+		for j := range b {
+			if r.Intn(20) == 0 {
+				b[j] = byte(r.Intn(256))
+			} else {
+				b[j] = byte('0' + r.Intn(10))
+			}
+		}
+		corpus = append(corpus, string(b))
+	}
+
+	var actual, expected error
+	for _, input := range corpus {
+		actual = ValidateConstantTime(input)
+		expected = Validate(input)
+		if referenceResult(actual) != referenceResult(expected) {
+			t.Fatalf(
+				"input %q generated actual output \"%v\" (expected \"%v\")",
+				input,
+				actual,
+				expected,
+			)
+		}
+	}
+}
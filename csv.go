@@ -0,0 +1,325 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrRTNColumnNotFound indicates that no column of a CSV contains RTNs with
+// enough confidence to be selected.
+var ErrRTNColumnNotFound = errors.New("rtn column not found")
+
+// ErrAmbiguousRTNColumn indicates that more than one column of a CSV is equally
+// likely to contain RTNs.
+var ErrAmbiguousRTNColumn = errors.New("ambiguous rtn column")
+
+// ErrMissingColumn indicates that a CSV row has too few fields to contain the
+// column being validated.
+var ErrMissingColumn = errors.New("missing column")
+
+// defaultSampleRows is the number of rows sampled when detecting an RTN column
+// if no sample size is provided.
+const defaultSampleRows = 100
+
+// detectSize is the number of bytes buffered when detecting the RTN column of a
+// CSV which is then read in full.
+const detectSize = 64 << 10
+
+// minColumnConfidence is the fraction of sampled values which must be valid
+// RTNs for a column to be selected.
+const minColumnConfidence = 0.5
+
+// DetectRTNColumn samples up to sampleRows rows of the provided CSV and returns
+// the zero-based index of the column most likely to contain RTNs. Columns are
+// scored by the fraction of their sampled values which are valid RTNs once
+// normalized as Normalize does, and the score of the selected column is
+// returned as its confidence. A header row is sampled like any other row and
+// only slightly lowers the confidence. If no column scores at least 0.5,
+// ErrRTNColumnNotFound is returned, and if the best score is shared by more
+// than one column, ErrAmbiguousRTNColumn is returned. A non-positive sampleRows
+// samples 100 rows.
+func DetectRTNColumn(r io.Reader, sampleRows int) (col int, confidence float64, err error) {
+	return detectRTNColumn(r, sampleRows, ',')
+}
+
+// detectRTNColumn detects the RTN column as DetectRTNColumn does, with fields
+// separated by the provided delimiter.
+func detectRTNColumn(r io.Reader, sampleRows int, comma rune) (col int, confidence float64, err error) {
+	if sampleRows <= 0 {
+		sampleRows = defaultSampleRows
+	}
+
+	var (
+		reader = csv.NewReader(r)
+		valid  []int
+		seen   []int
+		record []string
+		i      int
+		value  string
+	)
+
+	// Rows need not have a consistent number of fields
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	// Tally the valid RTNs and total values in each column of the sampled rows
+	for row := 0; row < sampleRows; row++ {
+		record, err = reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return -1, 0, err
+		}
+
+		for len(valid) < len(record) {
+			valid = append(valid, 0)
+			seen = append(seen, 0)
+		}
+
+		for i, value = range record {
+			seen[i]++
+			if ValidateLoose(value) == nil {
+				valid[i]++
+			}
+		}
+	}
+
+	var (
+		score float64
+		tied  bool
+	)
+
+	// Select the column with the highest fraction of valid RTNs
+	col = -1
+	for i = range valid {
+		score = float64(valid[i]) / float64(seen[i])
+		switch {
+		case score > confidence:
+			col, confidence, tied = i, score, false
+		case score == confidence && col >= 0:
+			tied = true
+		}
+	}
+
+	if col < 0 || confidence < minColumnConfidence {
+		return -1, confidence, ErrRTNColumnNotFound
+	}
+	if tied {
+		return -1, confidence, ErrAmbiguousRTNColumn
+	}
+
+	return col, confidence, nil
+}
+
+// CSVIssue describes an invalid RTN found by ValidateCSVColumn.
+type CSVIssue struct {
+	Row   int    // Row is the one-based row number, including header rows
+	Value string // Value is the raw value of the field
+	Err   error
+}
+
+// csvConfig holds the configuration built from a set of CSVOptions.
+type csvConfig struct {
+	skipHeader int
+	columnName string
+	loose      bool
+	scheme     Scheme
+	comma      rune
+	redaction  *RedactionPolicy
+}
+
+// CSVOption configures the behavior of ValidateCSVColumn.
+type CSVOption func(*csvConfig)
+
+// SkipHeader skips the first n rows of the CSV rather than validating them.
+func SkipHeader(n int) CSVOption {
+	return func(c *csvConfig) {
+		c.skipHeader = n
+	}
+}
+
+// ColumnByName selects the column to validate by matching the provided name
+// against the fields of the first row, ignoring case and surrounding
+// whitespace, in place of the provided column index. The first row is skipped
+// as if by SkipHeader(1) unless more rows are skipped.
+func ColumnByName(name string) CSVOption {
+	return func(c *csvConfig) {
+		c.columnName = name
+	}
+}
+
+// Loose normalizes values as Normalize does and restores a single leading zero
+// lost from values one digit short before validating them.
+func Loose() CSVOption {
+	return func(c *csvConfig) {
+		c.loose = true
+	}
+}
+
+// WithCSVScheme causes values to be validated under the provided scheme rather
+// than as ABA RTNs. Detecting the column from a negative index still looks for
+// ABA RTNs.
+func WithCSVScheme(s Scheme) CSVOption {
+	return func(c *csvConfig) {
+		c.scheme = s
+	}
+}
+
+// WithCSVRedaction causes the value and error of each issue to be redacted
+// under the provided policy, as RedactionPolicy.Redact and
+// RedactionPolicy.RedactError do, so that reports built from them don't expose
+// RTNs. Values are validated before they are redacted.
+func WithCSVRedaction(p RedactionPolicy) CSVOption {
+	return func(c *csvConfig) {
+		c.redaction = &p
+	}
+}
+
+// withComma causes fields to be separated by the provided delimiter rather than
+// a comma.
+func withComma(comma rune) CSVOption {
+	return func(c *csvConfig) {
+		c.comma = comma
+	}
+}
+
+// ValidateCSVColumn validates the RTN in the provided zero-based column of
+// every row of the provided CSV and returns an issue for each which is invalid.
+// Values are validated as Parse does unless Loose or WithCSVScheme is provided.
+// Rows with too few fields produce an issue with ErrMissingColumn rather than
+// ending validation. A negative column is detected from the leading rows of the
+// CSV as DetectRTNColumn does, returning its errors if no single column is
+// found. If ColumnByName is provided and no field of the header matches,
+// ErrRTNColumnNotFound is returned. An error reading the CSV is returned along
+// with the issues found before it.
+func ValidateCSVColumn(r io.Reader, column int, opts ...CSVOption) (issues []CSVIssue, err error) {
+	var c = csvConfig{comma: ','}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if column < 0 && c.columnName == "" {
+		if column, r, err = detectBufferedColumn(r, c.comma); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		reader = csv.NewReader(r)
+		record []string
+		row    int
+		value  string
+	)
+
+	// Rows need not have a consistent number of fields
+	reader.Comma = c.comma
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	if c.columnName != "" {
+		if record, err = reader.Read(); err != nil {
+			if err == io.EOF {
+				return nil, ErrRTNColumnNotFound
+			}
+			return nil, err
+		}
+		row++
+
+		column = -1
+		for i, name := range record {
+			if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(c.columnName)) {
+				column = i
+				break
+			}
+		}
+	}
+	if column < 0 {
+		return nil, ErrRTNColumnNotFound
+	}
+
+	for {
+		record, err = reader.Read()
+		if err == io.EOF {
+			return issues, nil
+		}
+		if err != nil {
+			return issues, err
+		}
+
+		row++
+		if row <= c.skipHeader {
+			continue
+		}
+
+		if column >= len(record) {
+			issues = append(issues, c.issue(row, "", ErrMissingColumn))
+			continue
+		}
+
+		value = record[column]
+		if err = validateCSVValue(value, &c); err != nil {
+			issues = append(issues, c.issue(row, value, err))
+		}
+	}
+}
+
+// issue returns an issue for the provided row, redacted if configured to be.
+func (c *csvConfig) issue(row int, value string, err error) CSVIssue {
+	if c.redaction != nil {
+		value, err = c.redaction.Redact(value), c.redaction.RedactError("", err)
+	}
+
+	return CSVIssue{Row: row, Value: value, Err: err}
+}
+
+// detectBufferedColumn detects the RTN column from a buffered prefix of the
+// provided reader, returning a reader which yields the entire input. If the
+// prefix is not the entire input, its final line is assumed to be truncated and
+// is ignored.
+func detectBufferedColumn(r io.Reader, comma rune) (column int, br *bufio.Reader, err error) {
+	br = bufio.NewReaderSize(r, detectSize)
+
+	sample, err := br.Peek(detectSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return -1, br, err
+	}
+	if err != io.EOF {
+		if i := bytes.LastIndexByte(sample, '\n'); i >= 0 {
+			sample = sample[:i+1]
+		}
+	}
+
+	column, _, err = detectRTNColumn(bytes.NewReader(sample), 0, comma)
+	return column, br, err
+}
+
+// validateCSVValue validates a single CSV field.
+func validateCSVValue(value string, c *csvConfig) (err error) {
+	if !c.loose {
+		return validateScheme(c.scheme, value, false)
+	}
+
+	if value, err = stripSeparators(value); err != nil {
+		return err
+	}
+
+	var length = 9
+	if c.scheme != nil {
+		length = c.scheme.Length()
+	}
+	if len(value) == length-1 {
+		value = "0" + value
+	}
+
+	return validateScheme(c.scheme, value, false)
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestDetectRTNColumn(t *testing.T) {
+	tests := []struct {
+		name               string
+		input              string
+		sampleRows         int
+		expectedCol        int
+		expectedConfidence float64
+		expectedError      error
+	}{
+		{
+			"header",
+			"name,routing,account\nAlice,322286188,1234\nBob,021200025,5678\nCarol,111000025,9012\n",
+			0,
+			1, 0.75, nil,
+		},
+		{
+			"no header",
+			"Alice,1234, 322286188 \nBob,5678,0212-0002-5\n",
+			0,
+			2, 1, nil,
+		},
+		{
+			"some invalid",
+			"322286188,x\n123456789,y\n021200025,z\n",
+			0,
+			0, 2.0 / 3.0, nil,
+		},
+		{
+			"sample limit",
+			"322286188,x\n021200025,y\nfoo,111000025\nbar,026014601\nbaz,322286188\n",
+			2,
+			0, 1, nil,
+		},
+		{
+			"ragged rows",
+			"a\nb,322286188\nc,021200025,extra\n",
+			0,
+			1, 1, nil,
+		},
+		{
+			"low confidence",
+			"322286188,x\nfoo,y\nbar,z\n",
+			0,
+			-1, 1.0 / 3.0, ErrRTNColumnNotFound,
+		},
+		{
+			"empty",
+			"",
+			0,
+			-1, 0, ErrRTNColumnNotFound,
+		},
+		{
+			"tie",
+			"322286188,021200025\n111000025,026014601\n",
+			0,
+			-1, 1, ErrAmbiguousRTNColumn,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				col, confidence, err := DetectRTNColumn(strings.NewReader(test.input), test.sampleRows)
+				if col != test.expectedCol || confidence != test.expectedConfidence || err != test.expectedError {
+					t.Fatalf(
+						"generated actual output %d, %f, \"%v\" (expected %d, %f, \"%v\")",
+						col,
+						confidence,
+						err,
+						test.expectedCol,
+						test.expectedConfidence,
+						test.expectedError,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestDetectRTNColumnMalformed(t *testing.T) {
+	_, _, err := DetectRTNColumn(strings.NewReader("\"unterminated,322286188\n"), 0)
+	if err == nil {
+		t.Fatalf("malformed CSV generated no error")
+	}
+}
+
+func TestValidateCSVColumn(t *testing.T) {
+	type issue struct {
+		row   int
+		value string
+		err   error
+	}
+
+	const input = "name,routing\n" +
+		"Alice,021200025\n" +
+		"Bob,021200026\n" +
+		"Carol\n" +
+		"Dave,\"0212-0002-5\"\n" +
+		"Erin,21200025\n" +
+		"Frank,02120O025\n"
+
+	tests := []struct {
+		name           string
+		column         int
+		opts           []CSVOption
+		expectedIssues []issue
+		expectedError  error
+	}{
+		{
+			"strict",
+			1,
+			[]CSVOption{SkipHeader(1)},
+			[]issue{
+				{3, "021200026", ErrChecksumMismatch},
+				{4, "", ErrMissingColumn},
+				{5, "0212-0002-5", ErrIncorrectLength},
+				{6, "21200025", ErrIncorrectLength},
+				{7, "02120O025", ErrInvalidCharacter},
+			},
+			nil,
+		},
+		{
+			"loose",
+			1,
+			[]CSVOption{SkipHeader(1), Loose()},
+			[]issue{
+				{3, "021200026", ErrChecksumMismatch},
+				{4, "", ErrMissingColumn},
+				{7, "02120O025", ErrInvalidCharacter},
+			},
+			nil,
+		},
+		{
+			"header not skipped",
+			1,
+			[]CSVOption{Loose()},
+			[]issue{
+				{1, "routing", ErrInvalidCharacter},
+				{3, "021200026", ErrChecksumMismatch},
+				{4, "", ErrMissingColumn},
+				{7, "02120O025", ErrInvalidCharacter},
+			},
+			nil,
+		},
+		{
+			"by name",
+			0,
+			[]CSVOption{ColumnByName(" Routing "), Loose(), SkipHeader(3)},
+			[]issue{
+				{4, "", ErrMissingColumn},
+				{7, "02120O025", ErrInvalidCharacter},
+			},
+			nil,
+		},
+		{
+			"by missing name",
+			1,
+			[]CSVOption{ColumnByName("aba")},
+			nil,
+			ErrRTNColumnNotFound,
+		},
+		{
+			"negative column",
+			-1,
+			nil,
+			nil,
+			ErrRTNColumnNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		issues, err := ValidateCSVColumn(strings.NewReader(input), test.column, test.opts...)
+		if !errors.Is(err, test.expectedError) || len(issues) != len(test.expectedIssues) {
+			t.Fatalf(
+				"test \"%s\" generated actual output %v, \"%v\" (expected %v, \"%v\")",
+				test.name,
+				issues,
+				err,
+				test.expectedIssues,
+				test.expectedError,
+			)
+		}
+
+		for i, expected := range test.expectedIssues {
+			if issues[i].Row != expected.row ||
+				issues[i].Value != expected.value ||
+				!errors.Is(issues[i].Err, expected.err) {
+				t.Fatalf(
+					"test \"%s\" generated actual issue %+v (expected %+v)",
+					test.name,
+					issues[i],
+					expected,
+				)
+			}
+		}
+	}
+}
+
+func TestValidateCSVColumnScheme(t *testing.T) {
+	const input = "id,code\n" +
+		"1,12340\n" +
+		"2,1236\n" +
+		"3,12-34-1\n" +
+		"4,021200025\n"
+
+	tests := []struct {
+		opts           []CSVOption
+		expectedValues []string
+		expectedErrors []error
+	}{
+		{
+			[]CSVOption{SkipHeader(1), WithCSVScheme(sumScheme{})},
+			[]string{"1236", "12-34-1", "021200025"},
+			[]error{ErrIncorrectLength, ErrIncorrectLength, ErrIncorrectLength},
+		},
+		{
+			[]CSVOption{SkipHeader(1), WithCSVScheme(sumScheme{}), Loose()},
+			[]string{"12-34-1", "021200025"},
+			[]error{ErrChecksumMismatch, ErrIncorrectLength},
+		},
+	}
+
+	for _, test := range tests {
+		issues, err := ValidateCSVColumn(strings.NewReader(input), 1, test.opts...)
+		if err != nil || len(issues) != len(test.expectedValues) {
+			t.Fatalf("scheme generated actual output %v, \"%v\" (expected %v)", issues, err, test.expectedValues)
+		}
+
+		for i, issue := range issues {
+			if issue.Value != test.expectedValues[i] || !errors.Is(issue.Err, test.expectedErrors[i]) {
+				t.Fatalf(
+					"scheme generated actual issue %+v (expected \"%s\", \"%v\")",
+					issue,
+					test.expectedValues[i],
+					test.expectedErrors[i],
+				)
+			}
+		}
+	}
+}
+
+func TestValidateCSVColumnRedaction(t *testing.T) {
+	const input = "name,routing\n" +
+		"Alice,021200025\n" +
+		"Bob,021200026\n" +
+		"Carol\n" +
+		"Dave,02120O025\n" +
+		"Erin,0212\n"
+
+	issues, err := ValidateCSVColumn(
+		strings.NewReader(input),
+		1,
+		SkipHeader(1),
+		WithCSVRedaction(RedactionPolicy{FullRedact: true}),
+	)
+	if err != nil || len(issues) != 4 {
+		t.Fatalf("redacting generated actual output %v, \"%v\"", issues, err)
+	}
+
+	for _, issue := range issues {
+		report := fmt.Sprintf("%s: %v", issue.Value, issue.Err)
+		if strings.IndexFunc(report, unicode.IsDigit) >= 0 {
+			t.Fatalf("redacting generated report containing digits \"%s\"", report)
+		}
+	}
+	if !errors.Is(issues[0].Err, ErrChecksumMismatch) || !errors.Is(issues[1].Err, ErrMissingColumn) {
+		t.Fatalf("redacting generated actual issues %v", issues)
+	}
+}
+
+func TestValidateCSVColumnMalformed(t *testing.T) {
+	issues, err := ValidateCSVColumn(strings.NewReader("021200025\n021200026\n\"unterminated\n"), 0)
+	if err == nil || len(issues) != 1 || issues[0].Row != 2 {
+		t.Fatalf("malformed input generated actual output %v, \"%v\"", issues, err)
+	}
+
+	_, err = ValidateCSVColumn(strings.NewReader(""), 0, ColumnByName("routing"))
+	if !errors.Is(err, ErrRTNColumnNotFound) {
+		t.Fatalf("empty input generated actual error \"%v\"", err)
+	}
+}
+
+func TestValidateCSVColumnDetect(t *testing.T) {
+	const input = "id,name,routing\n" +
+		"1,Alice,021200025\n" +
+		"2,Bob,021200026\n" +
+		"3,Carol,322286188\n"
+
+	issues, err := ValidateCSVColumn(strings.NewReader(input), -1, SkipHeader(1))
+	if err != nil || len(issues) != 1 || issues[0].Row != 3 || issues[0].Value != "021200026" {
+		t.Fatalf("detected column generated actual output %v, \"%v\"", issues, err)
+	}
+
+	// Rows beyond the sampled prefix are still validated
+	long := input + strings.Repeat("4,Dave,322286188\n", detectSize/16) + "5,Erin,322286189\n"
+	issues, err = ValidateCSVColumn(strings.NewReader(long), -1, SkipHeader(1))
+	if err != nil || len(issues) != 2 || issues[1].Value != "322286189" {
+		t.Fatalf("detected column of long input generated actual output %d issues, \"%v\"", len(issues), err)
+	}
+
+	_, err = ValidateCSVColumn(strings.NewReader("name,city\nAlice,Boston\n"), -1)
+	if !errors.Is(err, ErrRTNColumnNotFound) {
+		t.Fatalf("input without RTNs generated actual error \"%v\"", err)
+	}
+}
@@ -0,0 +1,425 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// ErrParticipantNotFound indicates that a routing number is not in a directory.
+var ErrParticipantNotFound = errors.New("participant not found")
+
+// ErrSuccessorNotFound indicates that a chain of successor routing numbers
+// leads to a routing number which is not in a directory.
+var ErrSuccessorNotFound = errors.New("successor not found")
+
+// ErrSuccessorCycle indicates that a chain of successor routing numbers leads
+// back to a routing number already in the chain.
+var ErrSuccessorCycle = errors.New("successor cycle")
+
+// DefaultSearchLimit is the greatest number of participants returned by Search
+// when a query does not set a limit.
+const DefaultSearchLimit = 100
+
+// Directory indexes the records of the FedACH participant directory by routing
+// number. It is safe for concurrent use, including lookups made while the
+// directory is being reloaded; each lookup observes either the participants
+// from before a reload or those from after it, never a mix of the two. The zero
+// value is an empty directory.
+type Directory struct {
+	snapshot atomic.Pointer[directorySnapshot]
+}
+
+// directorySnapshot is an immutable index of participants along with the time
+// it was built.
+type directorySnapshot struct {
+	index    map[string]ACHParticipant
+	entries  []directoryEntry
+	byState  map[string][]directoryEntry
+	loadedAt time.Time
+}
+
+// directoryEntry is a participant along with its normalized customer name, as
+// searched by Search. Entries are kept in ascending order of routing number.
+type directoryEntry struct {
+	participant ACHParticipant
+	name        string
+}
+
+// DirectoryStats describes the contents of a Directory.
+type DirectoryStats struct {
+	// Count is the number of participants in the directory.
+	Count int
+
+	// LastReload is the time the participants were last replaced, or the zero
+	// time if the directory has never been loaded.
+	LastReload time.Time
+}
+
+// NewDirectory builds a directory from the provided participants. If more than
+// one participant has the same routing number, the first is kept.
+func NewDirectory(participants []ACHParticipant) *Directory {
+	d := &Directory{}
+	d.Reload(participants)
+
+	return d
+}
+
+// Reload replaces the participants in the directory with those provided, as
+// NewDirectory builds them. The new index is built before it is swapped in, so
+// concurrent lookups are never blocked by a reload.
+func (d *Directory) Reload(participants []ACHParticipant) {
+	s := &directorySnapshot{
+		index:    make(map[string]ACHParticipant, len(participants)),
+		loadedAt: time.Now(),
+	}
+	for _, p := range participants {
+		if _, ok := s.index[p.RoutingNumber.String()]; !ok {
+			s.index[p.RoutingNumber.String()] = p
+			s.entries = append(s.entries, directoryEntry{participant: p, name: normalizeName(p.CustomerName)})
+		}
+	}
+
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].participant.RoutingNumber.String() < s.entries[j].participant.RoutingNumber.String()
+	})
+
+	s.byState = make(map[string][]directoryEntry)
+	for _, e := range s.entries {
+		state := strings.ToUpper(e.participant.State)
+		s.byState[state] = append(s.byState[state], e)
+	}
+
+	d.snapshot.Store(s)
+}
+
+// ReloadFromReader parses a FedACH participant directory from the provided
+// reader, as ParseFedACHDirectory does, and replaces the participants in the
+// directory with its records. If parsing fails, the directory is left
+// unchanged and the error is returned. The body of a response retrieved from
+// the Federal Reserve can be provided directly.
+func (d *Directory) ReloadFromReader(r io.Reader, opts ...DirectoryOption) (err error) {
+	participants, err := ParseFedACHDirectory(r, opts...)
+	if err != nil {
+		return err
+	}
+
+	d.Reload(participants)
+
+	return nil
+}
+
+// load returns the current snapshot of the directory, which is nil if the
+// directory has never been loaded.
+func (d *Directory) load() *directorySnapshot {
+	return d.snapshot.Load()
+}
+
+// Lookup returns the participant with the provided routing number, if any.
+func (d *Directory) Lookup(rtn string) (participant ACHParticipant, ok bool) {
+	if s := d.load(); s != nil {
+		participant, ok = s.index[rtn]
+	}
+
+	return participant, ok
+}
+
+// IsACHEligible determines whether the provided RTN is valid and belongs to a
+// participant in the directory which receives ACH entries itself. Participants
+// whose entries are sent to a new routing number, as indicated by a record
+// type of 2, are not eligible; ResolveSuccessor finds the routing number to
+// use instead.
+func (d *Directory) IsACHEligible(rtn string) bool {
+	if !isValid(rtn) {
+		return false
+	}
+
+	p, ok := d.Lookup(rtn)
+
+	return ok && p.RecordType != 2
+}
+
+// Len returns the number of participants in the directory.
+func (d *Directory) Len() int {
+	if s := d.load(); s != nil {
+		return len(s.index)
+	}
+
+	return 0
+}
+
+// Stats returns the number of participants in the directory and the time they
+// were last loaded, such as for alerting when the directory becomes stale.
+func (d *Directory) Stats() (stats DirectoryStats) {
+	if s := d.load(); s != nil {
+		stats.Count, stats.LastReload = len(s.index), s.loadedAt
+	}
+
+	return stats
+}
+
+// ResolveMissing calculates every completion of the provided RTN which
+// satisfies the checksum, as GetMissingDigits does, and returns the
+// participants in the directory with those routing numbers in ascending order.
+// Input must have between one and MaxMissingDigits digits replaced by the
+// character 'X', and produces the same errors as GetMissingDigits otherwise. If
+// no completion is in the directory, an empty slice is returned.
+func (d *Directory) ResolveMissing(rtn string) (participants []ACHParticipant, err error) {
+	candidates, err := GetMissingDigits(rtn)
+	if err != nil {
+		return nil, err
+	}
+
+	var s = d.load()
+
+	participants = []ACHParticipant{}
+	if s == nil {
+		return participants, nil
+	}
+
+	for _, candidate := range candidates {
+		if p, ok := s.index[candidate]; ok {
+			participants = append(participants, p)
+		}
+	}
+
+	return participants, nil
+}
+
+// Query describes the participants returned by Search. Empty fields match every
+// participant.
+type Query struct {
+	// Name matches participants whose customer names contain it. Letter case,
+	// periods, commas, and apostrophes are ignored, and other punctuation is
+	// treated as whitespace, so "J.P. Morgan" matches "JP MORGAN CHASE BANK"
+	// and "Wells-Fargo" matches "WELLS FARGO BANK".
+	Name string
+
+	// City matches participants in the city, ignoring letter case.
+	City string
+
+	// State matches participants in the state, ignoring letter case.
+	State string
+
+	// Limit is the greatest number of participants returned. If it is zero or
+	// negative, DefaultSearchLimit is used.
+	Limit int
+}
+
+// Search returns the participants in the directory matching the provided query
+// in ascending order of routing number, so results are stable across calls.
+// Queries which set State only consider the participants in that state;
+// otherwise every participant is scanned. If no participant matches, an empty
+// slice is returned.
+func (d *Directory) Search(q Query) (participants []ACHParticipant) {
+	var (
+		s       = d.load()
+		name    = normalizeName(q.Name)
+		city    = strings.TrimSpace(q.City)
+		limit   = q.Limit
+		entries []directoryEntry
+	)
+
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	participants = []ACHParticipant{}
+	if s == nil {
+		return participants
+	}
+
+	entries = s.entries
+	if state := strings.TrimSpace(q.State); state != "" {
+		entries = s.byState[strings.ToUpper(state)]
+	}
+
+	for _, e := range entries {
+		if len(participants) == limit {
+			break
+		}
+
+		if name != "" && !strings.Contains(e.name, name) {
+			continue
+		}
+		if city != "" && !strings.EqualFold(e.participant.City, city) {
+			continue
+		}
+
+		participants = append(participants, e.participant)
+	}
+
+	return participants
+}
+
+// normalizeName converts an institution name into the form compared by Search:
+// upper case, with periods, commas, and apostrophes removed, and with runs of
+// whitespace and other punctuation collapsed into a single space.
+func normalizeName(name string) string {
+	var (
+		sb    strings.Builder
+		space bool
+	)
+
+	sb.Grow(len(name))
+	for _, r := range name {
+		switch {
+		case r == '.' || r == ',' || r == '\'' || r == '’':
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r):
+			space = sb.Len() > 0
+		default:
+			if space {
+				sb.WriteByte(' ')
+				space = false
+			}
+			sb.WriteRune(unicode.ToUpper(r))
+		}
+	}
+
+	return sb.String()
+}
+
+// ResolveSuccessor follows the new routing numbers of merged and renumbered
+// institutions from the participant with the provided routing number until it
+// reaches a participant without one, returning that participant along with
+// the chain of routing numbers traversed, starting with the provided routing
+// number and ending with the final participant's. The routing number is
+// validated as Parse does, and ErrParticipantNotFound is returned if it is not
+// in the directory.
+//
+// A successor which is not in the directory produces ErrSuccessorNotFound and a
+// successor already in the chain produces ErrSuccessorCycle; in both cases the
+// chain traversed up to and including the offending routing number is
+// returned.
+func (d *Directory) ResolveSuccessor(rtn string) (final ACHParticipant, chain []string, err error) {
+	if err = Validate(rtn); err != nil {
+		return ACHParticipant{}, nil, err
+	}
+
+	var s = d.load()
+	if s == nil {
+		return ACHParticipant{}, nil, fmt.Errorf("%w: %s", ErrParticipantNotFound, rtn)
+	}
+
+	final, ok := s.index[rtn]
+	if !ok {
+		return ACHParticipant{}, nil, fmt.Errorf("%w: %s", ErrParticipantNotFound, rtn)
+	}
+
+	var seen = map[string]struct{}{rtn: {}}
+
+	chain = []string{rtn}
+	for {
+		next := final.NewRoutingNumber.String()
+		if next == "" || next == "000000000" {
+			return final, chain, nil
+		}
+
+		chain = append(chain, next)
+
+		if _, ok = seen[next]; ok {
+			return ACHParticipant{}, chain, fmt.Errorf("%w: %s", ErrSuccessorCycle, next)
+		}
+		seen[next] = struct{}{}
+
+		if final, ok = s.index[next]; !ok {
+			return ACHParticipant{}, chain, fmt.Errorf("%w: %s", ErrSuccessorNotFound, next)
+		}
+	}
+}
+
+// DirectoryDelta describes the differences between two directories. Each set
+// of participants is in ascending order of routing number.
+type DirectoryDelta struct {
+	// Added holds the participants only in the new directory.
+	Added []ACHParticipant
+
+	// Removed holds the participants only in the old directory.
+	Removed []ACHParticipant
+
+	// Modified holds the participants in both directories whose records
+	// differ.
+	Modified []ParticipantChange
+}
+
+// ParticipantChange describes a participant whose record differs between two
+// directories.
+type ParticipantChange struct {
+	Old ACHParticipant
+	New ACHParticipant
+}
+
+// IsEmpty determines whether the delta describes no differences.
+func (d DirectoryDelta) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Diff compares the participants of two directories by routing number,
+// reporting those added, removed, and modified from the old directory to the
+// new one, such as for review before a new directory is put into use. Either
+// directory may be nil, which is treated as empty.
+func Diff(from, to *Directory) (delta DirectoryDelta) {
+	var (
+		before = directoryEntries(from)
+		after  = directoryEntries(to)
+		i, j   int
+	)
+
+	// Both sets of entries are in ascending order of routing number, so they
+	// can be merged in a single pass
+	for i < len(before) || j < len(after) {
+		switch {
+		case j == len(after) ||
+			(i < len(before) && before[i].participant.RoutingNumber.String() < after[j].participant.RoutingNumber.String()):
+			delta.Removed = append(delta.Removed, before[i].participant)
+			i++
+		case i == len(before) ||
+			after[j].participant.RoutingNumber.String() < before[i].participant.RoutingNumber.String():
+			delta.Added = append(delta.Added, after[j].participant)
+			j++
+		default:
+			if !participantsEqual(before[i].participant, after[j].participant) {
+				delta.Modified = append(delta.Modified, ParticipantChange{Old: before[i].participant, New: after[j].participant})
+			}
+			i++
+			j++
+		}
+	}
+
+	return delta
+}
+
+// directoryEntries returns the entries of the provided directory, which may be
+// nil.
+func directoryEntries(d *Directory) []directoryEntry {
+	if d == nil {
+		return nil
+	}
+
+	if s := d.load(); s != nil {
+		return s.entries
+	}
+
+	return nil
+}
+
+// participantsEqual determines whether two participant records are the same,
+// comparing their change dates as instants.
+func participantsEqual(a, b ACHParticipant) bool {
+	if !a.ChangeDate.Equal(b.ChangeDate) {
+		return false
+	}
+
+	a.ChangeDate, b.ChangeDate = time.Time{}, time.Time{}
+
+	return a == b
+}
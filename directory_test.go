@@ -0,0 +1,491 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// loadTestDirectory builds a directory from the FedACH fixture.
+func loadTestDirectory(t *testing.T) *Directory {
+	f, err := os.Open("testdata/FedACHdir.txt")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %s", err)
+	}
+	defer f.Close()
+
+	participants, err := ParseFedACHDirectory(f)
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %s", err)
+	}
+
+	return NewDirectory(participants)
+}
+
+func TestDirectoryLookup(t *testing.T) {
+	d := loadTestDirectory(t)
+
+	if d.Len() != 5 {
+		t.Fatalf("fixture generated a directory of %d participants (expected 5)", d.Len())
+	}
+
+	p, ok := d.Lookup("026014601")
+	if !ok || p.CustomerName != "EXAMPLE NATIONAL BANK" || p.City != "NEW YORK" {
+		t.Fatalf("lookup generated actual output %+v, %t", p, ok)
+	}
+
+	if _, ok = d.Lookup("044000037"); ok {
+		t.Fatalf("lookup of a missing routing number succeeded")
+	}
+}
+
+func TestDirectoryResolveMissing(t *testing.T) {
+	d := loadTestDirectory(t)
+
+	tests := []struct {
+		input         string
+		expectedRTNs  []string
+		expectedError error
+	}{
+		{"0212000X5", []string{"021200025"}, nil},
+		{"02X20002X", []string{"021200025"}, nil},
+		{"X110000X5", []string{"011000015", "111000025"}, nil},
+		{"0440000X7", []string{}, nil},
+		{"XXXX00025", nil, ErrTooManyMissingDigits},
+		{"021200025", nil, ErrNoMissingDigits},
+		{"0212000X", nil, ErrIncorrectLength},
+		{"R212000X5", nil, ErrInvalidCharacter},
+	}
+
+	for _, test := range tests {
+		participants, err := d.ResolveMissing(test.input)
+
+		var rtns []string
+		if participants != nil {
+			rtns = []string{}
+		}
+		for _, p := range participants {
+			rtns = append(rtns, p.RoutingNumber.String())
+		}
+
+		if !reflect.DeepEqual(rtns, test.expectedRTNs) || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %q, \"%v\" (expected %q, \"%v\")",
+				test.input,
+				rtns,
+				err,
+				test.expectedRTNs,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestNewDirectoryDuplicates(t *testing.T) {
+	rtn, _ := Parse("021200025")
+	d := NewDirectory([]ACHParticipant{
+		{RoutingNumber: rtn, CustomerName: "FIRST"},
+		{RoutingNumber: rtn, CustomerName: "SECOND"},
+	})
+
+	if p, _ := d.Lookup("021200025"); d.Len() != 1 || p.CustomerName != "FIRST" {
+		t.Fatalf("duplicates generated actual directory of %d participants, %+v", d.Len(), p)
+	}
+}
+
+func TestDirectoryZeroValue(t *testing.T) {
+	var d Directory
+
+	if _, ok := d.Lookup("021200025"); ok {
+		t.Fatalf("lookup in an empty directory succeeded")
+	}
+
+	participants, err := d.ResolveMissing("0212000X5")
+	if err != nil || participants == nil || len(participants) != 0 {
+		t.Fatalf("empty directory resolved actual output %+v, \"%v\"", participants, err)
+	}
+
+	if stats := d.Stats(); stats.Count != 0 || !stats.LastReload.IsZero() {
+		t.Fatalf("empty directory generated actual stats %+v", stats)
+	}
+}
+
+func TestDirectoryReload(t *testing.T) {
+	var (
+		before = time.Now()
+		d      = loadTestDirectory(t)
+		stats  = d.Stats()
+	)
+
+	if stats.Count != 5 || stats.LastReload.Before(before) {
+		t.Fatalf("fixture generated actual stats %+v", stats)
+	}
+
+	rtn, _ := Parse("044000037")
+	d.Reload([]ACHParticipant{{RoutingNumber: rtn, CustomerName: "RELOADED"}})
+
+	if _, ok := d.Lookup("026014601"); ok {
+		t.Fatalf("lookup of a participant removed by a reload succeeded")
+	}
+	if p, ok := d.Lookup("044000037"); !ok || p.CustomerName != "RELOADED" {
+		t.Fatalf("lookup of a reloaded participant generated actual output %+v, %t", p, ok)
+	}
+	if reloaded := d.Stats(); reloaded.Count != 1 || reloaded.LastReload.Before(stats.LastReload) {
+		t.Fatalf("reload generated actual stats %+v (previously %+v)", reloaded, stats)
+	}
+}
+
+func TestDirectoryReloadFromReader(t *testing.T) {
+	f, err := os.Open("testdata/FedACHdir.txt")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %s", err)
+	}
+	defer f.Close()
+
+	var d Directory
+	if err = d.ReloadFromReader(f); err != nil {
+		t.Fatalf("fixture generated unexpected error \"%v\"", err)
+	}
+	stats := d.Stats()
+	if stats.Count != 5 {
+		t.Fatalf("fixture generated a directory of %d participants (expected 5)", stats.Count)
+	}
+
+	// A failed reload leaves the directory unchanged
+	err = d.ReloadFromReader(strings.NewReader("truncated\n"))
+	if !errors.Is(err, ErrMalformedRecord) {
+		t.Fatalf("generated actual error \"%v\" (expected \"%v\")", err, ErrMalformedRecord)
+	}
+	if reloaded := d.Stats(); reloaded != stats {
+		t.Fatalf("failed reload generated actual stats %+v (expected %+v)", reloaded, stats)
+	}
+}
+
+func TestDirectoryConcurrentReload(t *testing.T) {
+	var (
+		d            = loadTestDirectory(t)
+		participants = make([]ACHParticipant, 0, d.Len())
+		done         = make(chan struct{})
+		wg           sync.WaitGroup
+	)
+
+	for _, rtn := range []string{"011000015", "021200025", "026014601", "322286188", "111000025"} {
+		p, _ := d.Lookup(rtn)
+		participants = append(participants, p)
+	}
+
+	// Every lookup must observe a complete directory, either the full fixture
+	// or its first two participants
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if _, ok := d.Lookup("021200025"); !ok {
+					t.Errorf("lookup during a reload observed an incomplete directory")
+					return
+				}
+				if n := d.Stats().Count; n != 2 && n != 5 {
+					t.Errorf("stats during a reload observed %d participants", n)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		if i%2 == 0 {
+			d.Reload(participants[:2])
+		} else {
+			d.Reload(participants)
+		}
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+func TestDirectorySearch(t *testing.T) {
+	d := loadTestDirectory(t)
+
+	// Participants are added out of order to verify results are sorted
+	var participants []ACHParticipant
+	for _, p := range []struct{ rtn, name, city, state string }{
+		{"044000053", "JPMORGAN CHASE BANK, NA", "COLUMBUS", "OH"},
+		{"044000037", "JP MORGAN CHASE BANK", "COLUMBUS", "OH"},
+		{"071000013", "JP MORGAN CHASE BANK", "CHICAGO", "IL"},
+		{"044000040", "HUNTINGTON NATIONAL BANK", "COLUMBUS", "OH"},
+	} {
+		rtn, err := Parse(p.rtn)
+		if err != nil {
+			t.Fatalf("failed to parse \"%s\": %s", p.rtn, err)
+		}
+		participants = append(participants, ACHParticipant{RoutingNumber: rtn, CustomerName: p.name, City: p.city, State: p.state})
+	}
+	for _, rtn := range []string{"011000015", "021200025", "026014601", "322286188", "111000025"} {
+		p, _ := d.Lookup(rtn)
+		participants = append(participants, p)
+	}
+	d.Reload(participants)
+
+	tests := []struct {
+		query        Query
+		expectedRTNs []string
+	}{
+		{Query{Name: "J.P. Morgan"}, []string{"044000037", "071000013"}},
+		{Query{Name: "jp  morgan chase", State: "oh"}, []string{"044000037"}},
+		{Query{Name: "chase", City: "Columbus", State: "OH"}, []string{"044000037", "044000053"}},
+		{Query{Name: "chase bank na"}, []string{"044000053"}},
+		{Query{City: "columbus"}, []string{"044000037", "044000040", "044000053"}},
+		{Query{State: "OH", Limit: 2}, []string{"044000037", "044000040"}},
+		{Query{Name: "Bank & Trust"}, []string{"111000025"}},
+		{Query{Name: "example national"}, []string{"021200025", "026014601"}},
+		{Query{Name: "example national", State: "NY"}, []string{"026014601"}},
+		{Query{Name: "chase", State: "NY"}, []string{}},
+		{Query{State: "ZZ"}, []string{}},
+		{
+			Query{},
+			[]string{
+				"011000015", "021200025", "026014601", "044000037", "044000040",
+				"044000053", "071000013", "111000025", "322286188",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		participants := d.Search(test.query)
+
+		rtns := []string{}
+		for _, p := range participants {
+			rtns = append(rtns, p.RoutingNumber.String())
+		}
+
+		if participants == nil || !reflect.DeepEqual(rtns, test.expectedRTNs) {
+			t.Fatalf(
+				"query %+v generated actual output %q (expected %q)",
+				test.query,
+				rtns,
+				test.expectedRTNs,
+			)
+		}
+	}
+}
+
+func TestDirectorySearchLimit(t *testing.T) {
+	var participants = make([]ACHParticipant, DefaultSearchLimit+1)
+	for i := range participants {
+		rtn, _ := Complete(padLeadingZeros(strconv.Itoa(1000000+i), 8))
+		participants[i].RoutingNumber, _ = Parse(rtn)
+	}
+
+	d := NewDirectory(participants)
+	if n := len(d.Search(Query{})); n != DefaultSearchLimit {
+		t.Fatalf("search without a limit generated %d participants (expected %d)", n, DefaultSearchLimit)
+	}
+	if n := len(d.Search(Query{Limit: -1})); n != DefaultSearchLimit {
+		t.Fatalf("search with a negative limit generated %d participants (expected %d)", n, DefaultSearchLimit)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"J.P. Morgan", "JP MORGAN"},
+		{"  JPMorgan   Chase Bank, N.A. ", "JPMORGAN CHASE BANK NA"},
+		{"Wells-Fargo", "WELLS FARGO"},
+		{"People's Bank", "PEOPLES BANK"},
+		{"Bank & Trust", "BANK TRUST"},
+		{"", ""},
+	}
+
+	var actual string
+	for _, test := range tests {
+		actual = normalizeName(test.input)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\" (expected \"%s\")",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestDirectoryResolveSuccessor(t *testing.T) {
+	d := loadTestDirectory(t)
+
+	// Extend the fixture with a longer chain, cycles, a truncated chain, and an
+	// explicitly zero successor
+	participants := []ACHParticipant{}
+	for _, rtn := range []string{"011000015", "021200025", "026014601", "322286188", "111000025"} {
+		p, _ := d.Lookup(rtn)
+		participants = append(participants, p)
+	}
+	for _, link := range []struct{ rtn, next string }{
+		{"044000037", "322286188"},
+		{"044000040", "044000053"},
+		{"044000053", "044000040"},
+		{"071000013", "071000013"},
+		{"021200012", "026009593"},
+		{"000000000", "000000000"},
+	} {
+		var p ACHParticipant
+		p.RoutingNumber, _ = Parse(link.rtn)
+		if link.next != "" {
+			p.NewRoutingNumber, _ = Parse(link.next)
+		}
+		participants = append(participants, p)
+	}
+	d.Reload(participants)
+
+	tests := []struct {
+		input         string
+		expectedFinal string
+		expectedChain []string
+		expectedError error
+	}{
+		{"111000025", "111000025", []string{"111000025"}, nil},
+		{"322286188", "111000025", []string{"322286188", "111000025"}, nil},
+		{"044000037", "111000025", []string{"044000037", "322286188", "111000025"}, nil},
+		{"000000000", "000000000", []string{"000000000"}, nil},
+		{"044000040", "", []string{"044000040", "044000053", "044000040"}, ErrSuccessorCycle},
+		{"071000013", "", []string{"071000013", "071000013"}, ErrSuccessorCycle},
+		{"021200012", "", []string{"021200012", "026009593"}, ErrSuccessorNotFound},
+		{"026009593", "", nil, ErrParticipantNotFound},
+		{"322286189", "", nil, ErrChecksumMismatch},
+		{"32228618", "", nil, ErrIncorrectLength},
+	}
+
+	for _, test := range tests {
+		final, chain, err := d.ResolveSuccessor(test.input)
+		if final.RoutingNumber.String() != test.expectedFinal ||
+			!reflect.DeepEqual(chain, test.expectedChain) ||
+			!errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", %q, \"%v\" (expected \"%s\", %q, \"%v\")",
+				test.input,
+				final.RoutingNumber,
+				chain,
+				err,
+				test.expectedFinal,
+				test.expectedChain,
+				test.expectedError,
+			)
+		}
+	}
+
+	var empty Directory
+	if _, _, err := empty.ResolveSuccessor("322286188"); !errors.Is(err, ErrParticipantNotFound) {
+		t.Fatalf("empty directory generated actual error \"%v\" (expected \"%v\")", err, ErrParticipantNotFound)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := loadTestDirectory(t)
+
+	var participants []ACHParticipant
+	for _, rtn := range []string{"011000015", "021200025", "026014601", "111000025"} {
+		p, _ := old.Lookup(rtn)
+		if rtn == "026014601" {
+			p.Address = "1 NEW STREET"
+		}
+		participants = append(participants, p)
+	}
+
+	rtn, _ := Parse("044000037")
+	participants = append(participants, ACHParticipant{RoutingNumber: rtn, CustomerName: "ADDED"})
+
+	delta := Diff(old, NewDirectory(participants))
+
+	var added, removed, modified []string
+	for _, p := range delta.Added {
+		added = append(added, p.RoutingNumber.String())
+	}
+	for _, p := range delta.Removed {
+		removed = append(removed, p.RoutingNumber.String())
+	}
+	for _, c := range delta.Modified {
+		modified = append(modified, c.New.RoutingNumber.String())
+	}
+
+	if !reflect.DeepEqual(added, []string{"044000037"}) ||
+		!reflect.DeepEqual(removed, []string{"322286188"}) ||
+		!reflect.DeepEqual(modified, []string{"026014601"}) {
+		t.Fatalf("diff generated actual output added %q, removed %q, modified %q", added, removed, modified)
+	}
+	if c := delta.Modified[0]; c.Old.Address == c.New.Address || c.New.Address != "1 NEW STREET" {
+		t.Fatalf("diff generated actual change %+v", c)
+	}
+	if delta.IsEmpty() {
+		t.Fatalf("diff of different directories is empty")
+	}
+
+	// Identical and empty directories have no differences
+	if delta = Diff(old, loadTestDirectory(t)); !delta.IsEmpty() {
+		t.Fatalf("diff of identical directories generated actual output %+v", delta)
+	}
+	if delta = Diff(nil, &Directory{}); !delta.IsEmpty() {
+		t.Fatalf("diff of empty directories generated actual output %+v", delta)
+	}
+	if delta = Diff(nil, old); len(delta.Added) != 5 || len(delta.Removed) != 0 {
+		t.Fatalf("diff from an empty directory generated actual output %+v", delta)
+	}
+	if delta = Diff(old, nil); len(delta.Removed) != 5 || len(delta.Added) != 0 {
+		t.Fatalf("diff to an empty directory generated actual output %+v", delta)
+	}
+}
+
+func TestDirectoryIsACHEligible(t *testing.T) {
+	d := loadTestDirectory(t)
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"011000015", true},
+		{"021200025", true},
+		{"026014601", true},
+		{"111000025", true},
+
+		// Entries for this participant are sent to its new routing number
+		{"322286188", false},
+
+		// Valid, but not a participant
+		{"044000037", false},
+
+		{"02120002", false},
+		{"021200026", false},
+		{"0212O0025", false},
+	}
+
+	var actual bool
+	for _, test := range tests {
+		actual = d.IsACHEligible(test.input)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%s\" generated actual output %t (expected %t)",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
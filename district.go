@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+)
+
+// ErrUnassignedPrefix indicates that the first two digits of an RTN are not
+// assigned for the requested purpose.
+var ErrUnassignedPrefix = errors.New("unassigned prefix")
+
+// District describes the Federal Reserve district which an RTN is routed
+// through.
+type District struct {
+	// Number is the district number, from 1 to 12.
+	Number int
+
+	// Name is the name of the district's Federal Reserve Bank.
+	Name string
+
+	// City is the city of the district's Federal Reserve Bank head office.
+	City string
+
+	// HeadOffice indicates whether the RTN is routed through the district's head
+	// office, as indicated by a third digit of 1, rather than one of its
+	// branches.
+	HeadOffice bool
+}
+
+// districtNames holds the names of the Federal Reserve Banks, indexed by
+// district number.
+var districtNames = [...]string{
+	1:  "Federal Reserve Bank of Boston",
+	2:  "Federal Reserve Bank of New York",
+	3:  "Federal Reserve Bank of Philadelphia",
+	4:  "Federal Reserve Bank of Cleveland",
+	5:  "Federal Reserve Bank of Richmond",
+	6:  "Federal Reserve Bank of Atlanta",
+	7:  "Federal Reserve Bank of Chicago",
+	8:  "Federal Reserve Bank of St. Louis",
+	9:  "Federal Reserve Bank of Minneapolis",
+	10: "Federal Reserve Bank of Kansas City",
+	11: "Federal Reserve Bank of Dallas",
+	12: "Federal Reserve Bank of San Francisco",
+}
+
+// districtCities holds the cities of the Federal Reserve Bank head offices,
+// indexed by district number.
+var districtCities = [...]string{
+	1:  "Boston",
+	2:  "New York",
+	3:  "Philadelphia",
+	4:  "Cleveland",
+	5:  "Richmond",
+	6:  "Atlanta",
+	7:  "Chicago",
+	8:  "St. Louis",
+	9:  "Minneapolis",
+	10: "Kansas City",
+	11: "Dallas",
+	12: "San Francisco",
+}
+
+// FederalReserveInfo validates the provided RTN and returns the Federal
+// Reserve district it is routed through, including the name and head office
+// city of its Federal Reserve Bank. Primary institutions (prefixes 01-12),
+// thrift institutions (21-32), and electronic transactions (61-72) map to
+// districts 1-12. Any other prefix, including those for government (00) and
+// traveler's checks (80), produces ErrUnassignedPrefix.
+func FederalReserveInfo(rtn string) (district District, err error) {
+	if err = Validate(rtn); err != nil {
+		return District{}, err
+	}
+
+	number, ok := districtNumber(prefixOf(rtn))
+	if !ok {
+		return District{}, ErrUnassignedPrefix
+	}
+
+	return District{
+		Number:     number,
+		Name:       districtNames[number],
+		City:       districtCities[number],
+		HeadOffice: rtn[2] == '1',
+	}, nil
+}
+
+// districtNumber returns the Federal Reserve district mapped to the provided
+// two-digit prefix, if any.
+func districtNumber(prefix int) (number int, ok bool) {
+	switch classifyPrefix(prefix) {
+	case ClassPrimary:
+		return prefix, true
+	case ClassThrift:
+		return prefix - 20, true
+	case ClassElectronic:
+		return prefix - 60, true
+	}
+
+	return 0, false
+}
+
+// prefixOf returns the value of the first two digits of the provided RTN,
+// which must already be known to begin with two digits.
+func prefixOf(rtn string) int {
+	return int(rtn[0]-'0')*10 + int(rtn[1]-'0')
+}
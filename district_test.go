@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFederalReserveInfo(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedDistrict District
+		expectedError    error
+	}{
+		{"1234", District{}, ErrIncorrectLength},
+		{"R00000000", District{}, ErrInvalidCharacter},
+		{"123456789", District{}, ErrChecksumMismatch},
+		{"000000000", District{}, ErrUnassignedPrefix},
+		{"130000006", District{}, ErrUnassignedPrefix},
+		{"200000004", District{}, ErrUnassignedPrefix},
+		{"330000000", District{}, ErrUnassignedPrefix},
+		{"600000002", District{}, ErrUnassignedPrefix},
+		{"730000008", District{}, ErrUnassignedPrefix},
+		{"800000006", District{}, ErrUnassignedPrefix},
+		{"990000000", District{}, ErrUnassignedPrefix},
+		{"011000015", District{1, "Federal Reserve Bank of Boston", "Boston", true}, nil},
+		{"021200025", District{2, "Federal Reserve Bank of New York", "New York", true}, nil},
+		{"026014601", District{2, "Federal Reserve Bank of New York", "New York", false}, nil},
+		{"091000022", District{9, "Federal Reserve Bank of Minneapolis", "Minneapolis", true}, nil},
+		{"121000358", District{12, "Federal Reserve Bank of San Francisco", "San Francisco", true}, nil},
+		{"211000022", District{1, "Federal Reserve Bank of Boston", "Boston", true}, nil},
+		{"322286188", District{12, "Federal Reserve Bank of San Francisco", "San Francisco", false}, nil},
+		{"611000017", District{1, "Federal Reserve Bank of Boston", "Boston", true}, nil},
+		{"721000017", District{12, "Federal Reserve Bank of San Francisco", "San Francisco", true}, nil},
+	}
+
+	for _, test := range tests {
+		district, err := FederalReserveInfo(test.input)
+		if district != test.expectedDistrict || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %+v, \"%v\" (expected %+v, \"%v\")",
+				test.input,
+				district,
+				err,
+				test.expectedDistrict,
+				test.expectedError,
+			)
+		}
+	}
+}
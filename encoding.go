@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonNull is the JSON encoding of a null value.
+var jsonNull = []byte("null")
+
+// MarshalJSON encodes the RTN as a JSON string in its canonical 9-digit form,
+// including any leading zeros. The zero value is encoded as null.
+func (r RTN) MarshalJSON() ([]byte, error) {
+	if r.IsZero() {
+		return jsonNull, nil
+	}
+
+	return json.Marshal(r.value)
+}
+
+// UnmarshalJSON decodes and validates an RTN from a JSON string or number.
+// Numbers are zero-padded to 9 digits before validation, for compatibility with
+// systems which have lost the leading zeros of RTNs stored as integers. A null
+// value decodes to the zero value. Invalid RTNs produce the same errors as
+// Parse.
+func (r *RTN) UnmarshalJSON(data []byte) (err error) {
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, jsonNull) {
+		*r = RTN{}
+		return nil
+	}
+
+	var s string
+	if len(data) > 0 && data[0] == '"' {
+		if err = json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	} else {
+		// Numbers must be made up of digits alone, so signs, fractions, and
+		// exponents are rejected as invalid characters
+		s = padLeadingZeros(string(data), 9)
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+// MarshalText encodes the RTN in its canonical 9-digit form. The zero value is
+// encoded as an empty string, so that omitted values aren't mistaken for a
+// valid RTN.
+func (r RTN) MarshalText() ([]byte, error) {
+	return []byte(r.value), nil
+}
+
+// UnmarshalText decodes and validates an RTN from its 9-digit form. Empty input
+// decodes to the zero value. Invalid RTNs produce the same errors as Parse.
+func (r *RTN) UnmarshalText(text []byte) (err error) {
+	if len(text) == 0 {
+		*r = RTN{}
+		return nil
+	}
+
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+// binaryLength is the length of the binary encoding of an RTN.
+const binaryLength = 4
+
+// MarshalBinary encodes the RTN as the 4-byte, big-endian form of the integer
+// produced by Encode. The zero value is encoded as an empty slice.
+func (r RTN) MarshalBinary() ([]byte, error) {
+	if r.IsZero() {
+		return []byte{}, nil
+	}
+
+	v, err := Encode(r.value)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binaryLength)
+	binary.BigEndian.PutUint32(data, v)
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes and validates an RTN encoded by MarshalBinary. Empty
+// input decodes to the zero value. Input of any length other than 0 or 4 bytes
+// produces ErrIncorrectLength, and invalid RTNs produce the same errors as
+// Decode.
+func (r *RTN) UnmarshalBinary(data []byte) (err error) {
+	switch len(data) {
+	case 0:
+		*r = RTN{}
+		return nil
+	case binaryLength:
+	default:
+		return fmt.Errorf("%w: %d bytes (expected %d)", ErrIncorrectLength, len(data), binaryLength)
+	}
+
+	rtn, err := Decode(binary.BigEndian.Uint32(data))
+	if err != nil {
+		return err
+	}
+
+	*r = RTN{value: rtn}
+	return nil
+}
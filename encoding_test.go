@@ -0,0 +1,279 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// Ensure RTN satisfies the encoding interfaces.
+var (
+	_ json.Marshaler             = RTN{}
+	_ json.Unmarshaler           = (*RTN)(nil)
+	_ encoding.TextMarshaler     = RTN{}
+	_ encoding.TextUnmarshaler   = (*RTN)(nil)
+	_ encoding.BinaryMarshaler   = RTN{}
+	_ encoding.BinaryUnmarshaler = (*RTN)(nil)
+	_ fmt.Stringer               = RTN{}
+)
+
+func TestRTNMarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", `null`},
+		{"026014601", `"026014601"`},
+		{"000000000", `"000000000"`},
+		{"322286188", `"322286188"`},
+	}
+
+	for _, test := range tests {
+		var rtn RTN
+		if test.input != "" {
+			rtn, _ = Parse(test.input)
+		}
+
+		actual, err := json.Marshal(rtn)
+		if err != nil || string(actual) != test.expected {
+			t.Fatalf(
+				"input \"%s\" generated actual output %s, \"%v\" (expected %s)",
+				test.input,
+				actual,
+				err,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestRTNUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedRTN   string
+		expectedError error
+	}{
+		{`"026014601"`, "026014601", nil},
+		{`"322286188"`, "322286188", nil},
+		{`26014601`, "026014601", nil},
+		{`322286188`, "322286188", nil},
+		{`0`, "000000000", nil},
+		{`null`, "", nil},
+		{`"26014601"`, "", ErrIncorrectLength},
+		{`"123456789"`, "", ErrChecksumMismatch},
+		{`123456789`, "", ErrChecksumMismatch},
+		{`"02601460O"`, "", ErrInvalidCharacter},
+		{`1234567890`, "", ErrIncorrectLength},
+		{`-26014601`, "", ErrInvalidCharacter},
+		{`2601460.1`, "", ErrInvalidCharacter},
+		{`2.6014e7`, "", ErrInvalidCharacter},
+		{`""`, "", ErrIncorrectLength},
+	}
+
+	for _, test := range tests {
+		var rtn RTN
+		err := json.Unmarshal([]byte(test.input), &rtn)
+		if rtn.String() != test.expectedRTN || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input %s generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				rtn,
+				err,
+				test.expectedRTN,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestRTNJSONStruct(t *testing.T) {
+	type account struct {
+		Routing  RTN  `json:"routing"`
+		Optional *RTN `json:"optional"`
+	}
+
+	var a account
+	if err := json.Unmarshal([]byte(`{"routing":26014601,"optional":null}`), &a); err != nil {
+		t.Fatalf("decoding failed: %s", err)
+	}
+	if a.Routing.String() != "026014601" || a.Optional != nil {
+		t.Fatalf("decoded unexpected value %+v", a)
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil || string(data) != `{"routing":"026014601","optional":null}` {
+		t.Fatalf("encoding generated actual output %s, \"%v\"", data, err)
+	}
+
+	err = json.Unmarshal([]byte(`{"routing":"123456789"}`), &a)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("decoding an invalid RTN generated actual error \"%v\"", err)
+	}
+}
+
+func TestRTNMarshalText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"026014601", "026014601"},
+		{"000000000", "000000000"},
+	}
+
+	for _, test := range tests {
+		var rtn RTN
+		if test.input != "" {
+			rtn, _ = Parse(test.input)
+		}
+
+		actual, err := rtn.MarshalText()
+		if err != nil || string(actual) != test.expected {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\")",
+				test.input,
+				actual,
+				err,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestRTNUnmarshalText(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedRTN   string
+		expectedError error
+	}{
+		{"026014601", "026014601", nil},
+		{"322286188", "322286188", nil},
+		{"", "", nil},
+		{"26014601", "", ErrIncorrectLength},
+		{"02601460O", "", ErrInvalidCharacter},
+		{"123456789", "", ErrChecksumMismatch},
+	}
+
+	for _, test := range tests {
+		var rtn RTN
+		err := rtn.UnmarshalText([]byte(test.input))
+		if rtn.String() != test.expectedRTN || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				rtn,
+				err,
+				test.expectedRTN,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestRTNTextMapKey(t *testing.T) {
+	rtn, _ := Parse("026014601")
+
+	data, err := json.Marshal(map[RTN]int{rtn: 1})
+	if err != nil || string(data) != `{"026014601":1}` {
+		t.Fatalf("encoding a map generated actual output %s, \"%v\"", data, err)
+	}
+
+	var m map[RTN]int
+	if err = json.Unmarshal([]byte(`{"322286188":2}`), &m); err != nil || len(m) != 1 {
+		t.Fatalf("decoding a map generated actual output %v, \"%v\"", m, err)
+	}
+
+	err = json.Unmarshal([]byte(`{"123456789":3}`), &m)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("decoding an invalid map key generated actual error \"%v\"", err)
+	}
+}
+
+func TestRTNBinary(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []byte
+	}{
+		{"", []byte{}},
+		{"026014601", []byte{0x01, 0x8c, 0xf3, 0x89}},
+		{"322286188", []byte{0x13, 0x35, 0xb2, 0x6c}},
+		{"000000000", []byte{0x00, 0x00, 0x00, 0x00}},
+	}
+
+	for _, test := range tests {
+		var rtn RTN
+		if test.input != "" {
+			rtn, _ = Parse(test.input)
+		}
+
+		actual, err := rtn.MarshalBinary()
+		if err != nil || !bytes.Equal(actual, test.expected) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %x, \"%v\" (expected %x)",
+				test.input,
+				actual,
+				err,
+				test.expected,
+			)
+		}
+
+		var decoded RTN
+		if err = decoded.UnmarshalBinary(actual); err != nil || decoded != rtn {
+			t.Fatalf("input \"%s\" decoded to \"%s\", \"%v\"", test.input, decoded, err)
+		}
+	}
+}
+
+func TestRTNUnmarshalBinaryErrors(t *testing.T) {
+	tests := []struct {
+		input    []byte
+		expected error
+	}{
+		{[]byte{0x01, 0x8c, 0xf3}, ErrIncorrectLength},
+		{[]byte{0x01, 0x8c, 0xf3, 0x89, 0x00}, ErrIncorrectLength},
+		{[]byte{0x07, 0x5b, 0xcd, 0x15}, ErrChecksumMismatch},
+		{[]byte{0xff, 0xff, 0xff, 0xff}, ErrOutOfRange},
+	}
+
+	for _, test := range tests {
+		var rtn RTN
+		err := rtn.UnmarshalBinary(test.input)
+		if !rtn.IsZero() || !errors.Is(err, test.expected) {
+			t.Fatalf(
+				"input %x generated actual output \"%s\", \"%v\" (expected \"%v\")",
+				test.input,
+				rtn,
+				err,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestRTNGob(t *testing.T) {
+	type account struct {
+		Routing RTN
+	}
+
+	var (
+		buf      bytes.Buffer
+		original account
+		decoded  account
+	)
+	original.Routing, _ = Parse("026014601")
+
+	if err := gob.NewEncoder(&buf).Encode(original); err != nil {
+		t.Fatalf("encoding failed: %s", err)
+	}
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil || decoded != original {
+		t.Fatalf("decoding generated actual output %+v, \"%v\"", decoded, err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"unicode/utf8"
+)
+
+// EnumerateValidFunc calls the provided function with every valid RTN
+// beginning with the provided prefix, in ascending order, until the function
+// returns false. A prefix of n digits produces 10^(8-n) RTNs, since the check
+// digit is computed from the first 8 digits rather than searched for. RTNs are
+// produced one at a time, so even the empty prefix, which produces every valid
+// RTN, requires little memory.
+//
+// The prefix must be at most 8 digits; a longer prefix produces
+// ErrIncorrectLength and a non-digit produces an *InvalidCharacterError, before
+// the function is called.
+func EnumerateValidFunc(prefix string, fn func(rtn string) bool) (err error) {
+	if len(prefix) > 8 {
+		return ErrIncorrectLength
+	}
+
+	if i := indexNonDigit(prefix); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(prefix[i:])
+		return &InvalidCharacterError{Index: i, Rune: r}
+	}
+
+	var (
+		buf [9]byte
+		n   = len(prefix)
+		i   int
+	)
+
+	copy(buf[:], prefix)
+	for i = n; i < 8; i++ {
+		buf[i] = '0'
+	}
+
+	for {
+		// The first 8 bytes are digits, so the weighted sum cannot fail
+		sum, _ := weightedSum(buf[:8])
+		buf[8] = byte('0' + (10-sum%10)%10)
+
+		if !fn(string(buf[:])) {
+			return nil
+		}
+
+		// Advance the digits following the prefix as an odometer, stopping once
+		// every combination has been produced
+		for i = 7; i >= n; i-- {
+			if buf[i] != '9' {
+				buf[i]++
+				break
+			}
+			buf[i] = '0'
+		}
+		if i < n {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEnumerateValidFunc(t *testing.T) {
+	tests := []struct {
+		prefix        string
+		expectedCount int
+		expectedError error
+	}{
+		{"021200025", 0, ErrIncorrectLength},
+		{"0212R", 0, ErrInvalidCharacter},
+		{"0212é", 0, ErrInvalidCharacter},
+		{"02120002", 1, nil},
+		{"0212000", 10, nil},
+		{"021200", 100, nil},
+		{"0212", 10000, nil},
+		{"99", 1000000, nil},
+	}
+
+	for _, test := range tests {
+		var (
+			count    int
+			previous string
+		)
+
+		err := EnumerateValidFunc(
+			test.prefix,
+			func(rtn string) bool {
+				if err := Validate(rtn); err != nil {
+					t.Fatalf("prefix \"%s\" generated invalid RTN \"%s\": %s", test.prefix, rtn, err)
+				}
+				if rtn[:len(test.prefix)] != test.prefix {
+					t.Fatalf("prefix \"%s\" generated RTN \"%s\" with another prefix", test.prefix, rtn)
+				}
+				if rtn <= previous {
+					t.Fatalf("prefix \"%s\" generated RTN \"%s\" after \"%s\"", test.prefix, rtn, previous)
+				}
+
+				count++
+				previous = rtn
+
+				return true
+			},
+		)
+		if count != test.expectedCount || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"prefix \"%s\" generated actual output %d, \"%v\" (expected %d, \"%v\")",
+				test.prefix,
+				count,
+				err,
+				test.expectedCount,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestEnumerateValidFuncStop(t *testing.T) {
+	var rtns []string
+
+	err := EnumerateValidFunc("", func(rtn string) bool {
+		rtns = append(rtns, rtn)
+		return len(rtns) < 3
+	})
+
+	expected := []string{"000000000", "000000013", "000000026"}
+	if err != nil || !reflect.DeepEqual(rtns, expected) {
+		t.Fatalf("generated actual output %q, \"%v\" (expected %q)", rtns, err, expected)
+	}
+}
+
+func TestEnumerateValidFuncMatchesGetMissingDigits(t *testing.T) {
+	// Both must produce the same RTNs for the same constraint
+	var enumerated []string
+	_ = EnumerateValidFunc("0212000", func(rtn string) bool {
+		enumerated = append(enumerated, rtn)
+		return true
+	})
+
+	candidates, err := GetMissingDigits("0212000XX")
+	if err != nil || !reflect.DeepEqual(enumerated, candidates) {
+		t.Fatalf("generated actual output %q (expected %q, \"%v\")", enumerated, candidates, err)
+	}
+}
+
+func BenchmarkEnumerateValidFunc(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = EnumerateValidFunc("021200", func(rtn string) bool { return true })
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// InvalidCharacterError describes an invalid character found within an RTN. It
+// matches ErrInvalidCharacter, so callers may continue to use errors.Is.
+type InvalidCharacterError struct {
+	// Index is the byte offset of the invalid character within the input.
+	Index int
+
+	// Rune is the invalid character.
+	Rune rune
+}
+
+// Error returns a description of the invalid character.
+func (e *InvalidCharacterError) Error() string {
+	return fmt.Sprintf("%s %q at index %d", ErrInvalidCharacter, e.Rune, e.Index)
+}
+
+// Unwrap returns ErrInvalidCharacter.
+func (e *InvalidCharacterError) Unwrap() error {
+	return ErrInvalidCharacter
+}
+
+// ChecksumError describes an RTN whose check digit does not match its remaining
+// digits. It matches ErrChecksumMismatch, so callers may continue to use
+// errors.Is.
+type ChecksumError struct {
+	// Got is the weighted checksum of the RTN modulo 10, which is zero for valid
+	// RTNs.
+	Got int
+}
+
+// Error returns a description of the checksum mismatch.
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s: checksum remainder %d", ErrChecksumMismatch, e.Got)
+}
+
+// Unwrap returns ErrChecksumMismatch.
+func (e *ChecksumError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// CheckDigitError describes a check digit, provided separately from the rest of
+// an RTN, which does not match the one computed from the remaining digits. It
+// matches ErrChecksumMismatch, so callers may continue to use errors.Is.
+type CheckDigitError struct {
+	// Expected is the check digit computed from the remaining digits.
+	Expected int
+
+	// Actual is the check digit provided.
+	Actual int
+}
+
+// Error returns a description of the check digit mismatch.
+func (e *CheckDigitError) Error() string {
+	return fmt.Sprintf("%s: check digit %d (expected %d)", ErrChecksumMismatch, e.Actual, e.Expected)
+}
+
+// Unwrap returns ErrChecksumMismatch.
+func (e *CheckDigitError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// RedactedError wraps an error along with the input which produced it, both
+// rendered under a RedactionPolicy so that the error can be echoed to logs and
+// users. Only its message is redacted; it matches the wrapped error with
+// errors.Is and errors.As.
+type RedactedError struct {
+	// Input is the redacted input, or empty if the input is not echoed.
+	Input string
+
+	// Err is the wrapped error.
+	Err error
+
+	// policy is the policy under which the error is rendered.
+	policy RedactionPolicy
+}
+
+// Error returns the redacted input followed by the message of the wrapped
+// error. If the policy has FullRedact set, every digit of the message is masked
+// as well, so the message never contains a digit.
+func (e *RedactedError) Error() string {
+	msg := e.Err.Error()
+	if e.policy.FullRedact {
+		msg = strings.Map(
+			func(r rune) rune {
+				if unicode.IsDigit(r) {
+					return e.policy.maskRune()
+				}
+				return r
+			},
+			msg,
+		)
+	}
+
+	if e.Input == "" {
+		return msg
+	}
+
+	return e.Input + ": " + msg
+}
+
+// Unwrap returns the wrapped error.
+func (e *RedactedError) Unwrap() error {
+	return e.Err
+}
+
+// detailError converts an ErrInvalidCharacter or ErrChecksumMismatch produced by
+// validating the provided RTN into an *InvalidCharacterError or *ChecksumError.
+// Other errors are returned unchanged.
+func detailError(rtn string, err error) error {
+	switch err {
+	case ErrInvalidCharacter:
+		for i, r := range rtn {
+			if _, ok := runeToDigit(r); !ok {
+				return &InvalidCharacterError{Index: i, Rune: r}
+			}
+		}
+	case ErrChecksumMismatch:
+		remainder, _ := ChecksumRemainder(rtn)
+		return &ChecksumError{Got: remainder}
+	}
+
+	return err
+}
+
+// ValidateAll validates the provided RTN as Validate does, but reports every
+// problem found rather than only the first. An incorrect length produces
+// ErrIncorrectLength, each invalid character produces an
+// *InvalidCharacterError, and a checksum mismatch, which can only be determined
+// when the RTN is 9 digits, produces a *ChecksumError. The problems are joined
+// with errors.Join, so errors.Is and errors.As match each of them. Validate
+// remains faster for RTNs which are expected to be valid.
+func ValidateAll(rtn string) (err error) {
+	var errs []error
+
+	if len(rtn) != 9 {
+		errs = append(errs, ErrIncorrectLength)
+	}
+
+	for i, r := range rtn {
+		if _, ok := runeToDigit(r); !ok {
+			errs = append(errs, &InvalidCharacterError{Index: i, Rune: r})
+		}
+	}
+
+	if len(errs) == 0 {
+		// The RTN is made up of 9 digits, so only the checksum can be wrong
+		if remainder, _ := ChecksumRemainder(rtn); remainder != 0 {
+			errs = append(errs, &ChecksumError{Got: remainder})
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInvalidCharacterError(t *testing.T) {
+	tests := []struct {
+		name          string
+		fn            func() error
+		expectedIndex int
+		expectedRune  rune
+	}{
+		{"Validate", func() error { return Validate("0212O0025") }, 4, 'O'},
+		{"Validate multi-byte", func() error { return Validate("0212é002") }, 4, 'é'},
+		{"ValidateBytes", func() error { return ValidateBytes([]byte("02120002\x00")) }, 8, 0},
+		{"GetMissingDigit", func() error { _, err := GetMissingDigit("X212O0025"); return err }, 4, 'O'},
+		{"GetMissingDigit multi-byte", func() error { _, err := GetMissingDigit("X22286é8"); return err }, 6, 'é'},
+		{"Parse", func() error { _, err := Parse("0212O0025"); return err }, 4, 'O'},
+		{"Parse multi-byte", func() error { _, err := Parse("0212é002"); return err }, 4, 'é'},
+		{"Normalize", func() error { _, err := Normalize(" 0212-O002-5"); return err }, 6, 'O'},
+		{"ValidateLoose", func() error { return ValidateLoose("⑆02120002S⑆") }, 11, 'S'},
+		{"GetMissingDigits", func() error { _, err := GetMissingDigits("X2120002l"); return err }, 8, 'l'},
+		{"Classify", func() error { _, err := Classify("B21200025"); return err }, 0, 'B'},
+		{"ValidateOCR", func() error { _, err := ValidateOCR("O2l2OO025"); return err }, 0, 'O'},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				err := test.fn()
+				if !errors.Is(err, ErrInvalidCharacter) {
+					t.Fatalf("generated actual error \"%v\" (expected \"%v\")", err, ErrInvalidCharacter)
+				}
+
+				var icErr *InvalidCharacterError
+				if !errors.As(err, &icErr) {
+					t.Fatalf("error \"%v\" is not an *InvalidCharacterError", err)
+				}
+				if icErr.Index != test.expectedIndex || icErr.Rune != test.expectedRune {
+					t.Fatalf(
+						"generated actual position %d, %q (expected %d, %q)",
+						icErr.Index,
+						icErr.Rune,
+						test.expectedIndex,
+						test.expectedRune,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestChecksumError(t *testing.T) {
+	tests := []struct {
+		name        string
+		fn          func() error
+		expectedGot int
+	}{
+		{"Validate", func() error { return Validate("123456789") }, 9},
+		{"ValidateBytes", func() error { return ValidateBytes([]byte("322286189")) }, 1},
+		{"Parse", func() error { _, err := Parse("123456789"); return err }, 9},
+		{"Parse off by one", func() error { _, err := Parse("322286189"); return err }, 1},
+		{"ValidateLoose", func() error { return ValidateLoose("0212-0002-6") }, 1},
+		{"ValidateOCR", func() error { _, err := ValidateOCR("O2l200026"); return err }, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				err := test.fn()
+				if !errors.Is(err, ErrChecksumMismatch) {
+					t.Fatalf("generated actual error \"%v\" (expected \"%v\")", err, ErrChecksumMismatch)
+				}
+
+				var csErr *ChecksumError
+				if !errors.As(err, &csErr) {
+					t.Fatalf("error \"%v\" is not a *ChecksumError", err)
+				}
+				if csErr.Got != test.expectedGot {
+					t.Fatalf("generated actual remainder %d (expected %d)", csErr.Got, test.expectedGot)
+				}
+			},
+		)
+	}
+}
+
+func TestErrorMessages(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{&InvalidCharacterError{Index: 4, Rune: 'O'}, "invalid character 'O' at index 4"},
+		{&CheckDigitError{Expected: 8, Actual: 7}, "checksum mismatch: check digit 7 (expected 8)"},
+		{&ChecksumError{Got: 7}, "checksum mismatch: checksum remainder 7"},
+	}
+
+	for _, test := range tests {
+		if test.err.Error() != test.expected {
+			t.Fatalf("generated actual message \"%s\" (expected \"%s\")", test.err, test.expected)
+		}
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedErrors []error
+	}{
+		{"021200025", nil},
+		{"021200026", []error{&ChecksumError{Got: 1}}},
+		{"02120002", []error{ErrIncorrectLength}},
+		{"02I20R02", []error{
+			ErrIncorrectLength,
+			&InvalidCharacterError{Index: 2, Rune: 'I'},
+			&InvalidCharacterError{Index: 5, Rune: 'R'},
+		}},
+		{"O2I2OOO2S", []error{
+			&InvalidCharacterError{Index: 0, Rune: 'O'},
+			&InvalidCharacterError{Index: 2, Rune: 'I'},
+			&InvalidCharacterError{Index: 4, Rune: 'O'},
+			&InvalidCharacterError{Index: 5, Rune: 'O'},
+			&InvalidCharacterError{Index: 6, Rune: 'O'},
+			&InvalidCharacterError{Index: 8, Rune: 'S'},
+		}},
+		{"0212é002", []error{&InvalidCharacterError{Index: 4, Rune: 'é'}}},
+		{"", []error{ErrIncorrectLength}},
+	}
+
+	for _, test := range tests {
+		err := ValidateAll(test.input)
+		if test.expectedErrors == nil {
+			if err != nil {
+				t.Fatalf("input \"%s\" generated actual error \"%v\" (expected none)", test.input, err)
+			}
+			continue
+		}
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			t.Fatalf("input \"%s\" generated actual error \"%v\" which is not joined", test.input, err)
+		}
+
+		actual := joined.Unwrap()
+		if len(actual) != len(test.expectedErrors) {
+			t.Fatalf(
+				"input \"%s\" generated %d errors \"%v\" (expected %d)",
+				test.input,
+				len(actual),
+				err,
+				len(test.expectedErrors),
+			)
+		}
+		for i, expected := range test.expectedErrors {
+			if actual[i].Error() != expected.Error() {
+				t.Fatalf(
+					"input \"%s\" generated actual error \"%v\" (expected \"%v\")",
+					test.input,
+					actual[i],
+					expected,
+				)
+			}
+		}
+	}
+
+	// Positional detail is available through errors.As
+	var charErr *InvalidCharacterError
+	if err := ValidateAll("02I20R02"); !errors.Is(err, ErrIncorrectLength) ||
+		!errors.Is(err, ErrInvalidCharacter) ||
+		errors.Is(err, ErrChecksumMismatch) ||
+		!errors.As(err, &charErr) ||
+		charErr.Index != 2 {
+		t.Fatalf("joined errors generated actual error \"%v\"", err)
+	}
+}
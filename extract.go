@@ -0,0 +1,198 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match is a candidate RTN found by Extract.
+type Match struct {
+	// Start and End are the byte offsets of the candidate within the text,
+	// including any separators
+	Start, End int
+
+	// RTN is the candidate with separators removed
+	RTN string
+
+	// Valid is false for candidates which fail the checksum, which are only
+	// returned when IncludeInvalid is provided
+	Valid bool
+}
+
+// extractConfig holds the configuration built from a set of ExtractOptions.
+type extractConfig struct {
+	includeInvalid bool
+}
+
+// ExtractOption configures the behavior of Extract.
+type ExtractOption func(*extractConfig)
+
+// IncludeInvalid causes Extract to also return 9-digit candidates which fail
+// the checksum, flagged as invalid.
+func IncludeInvalid() ExtractOption {
+	return func(c *extractConfig) {
+		c.includeInvalid = true
+	}
+}
+
+// Extract scans the provided text for RTNs and returns the candidates which
+// satisfy the checksum in the order they appear. A candidate is a group of
+// exactly 9 digits, optionally split by single spaces or hyphens as in
+// "021-200-025" or "021 200 025", which is not adjoined by letters, other
+// digits, or a decimal point or comma followed by a digit. Longer runs of
+// digits, such as phone numbers and account numbers, never produce candidates
+// from within them. When a space-separated group has more than 9 digits, each
+// of its space-separated parts is considered on its own. Candidates without
+// separators don't allocate.
+func Extract(s string, opts ...ExtractOption) (matches []Match) {
+	// The configuration escapes to the options, so it's only built when they
+	// are provided
+	var c extractConfig
+	if len(opts) > 0 {
+		var configured extractConfig
+		for _, opt := range opts {
+			opt(&configured)
+		}
+		c = configured
+	}
+
+	var (
+		i, start int
+		digits   int
+		spaced   bool
+	)
+
+	for i < len(s) {
+		if !isDigitByte(s[i]) {
+			i++
+			continue
+		}
+
+		// Extend the group over digits and single separators between digits
+		start, digits, spaced = i, 0, false
+		for i < len(s) {
+			if isDigitByte(s[i]) {
+				digits++
+				i++
+				continue
+			}
+			if (s[i] == ' ' || s[i] == '-') && i+1 < len(s) && isDigitByte(s[i+1]) {
+				spaced = spaced || s[i] == ' '
+				i++
+				continue
+			}
+			break
+		}
+
+		if digits == 9 {
+			matches = c.appendMatch(matches, s, start, i)
+			continue
+		}
+
+		if !spaced {
+			continue
+		}
+
+		// Consider each space-separated part of an overlong group
+		for partStart, j := start, start; j <= i; j++ {
+			if j < i && s[j] != ' ' {
+				continue
+			}
+
+			if countDigits(s[partStart:j]) == 9 {
+				matches = c.appendMatch(matches, s, partStart, j)
+			}
+			partStart = j + 1
+		}
+	}
+
+	return matches
+}
+
+// appendMatch appends the candidate RTN spanning s[start:end] to the provided
+// matches if it is bounded and is valid or invalid candidates are included.
+func (c *extractConfig) appendMatch(matches []Match, s string, start, end int) []Match {
+	if !isBoundaryBefore(s, start) || !isBoundaryAfter(s, end) {
+		return matches
+	}
+
+	var rtn = s[start:end]
+	if len(rtn) != 9 {
+		var b strings.Builder
+		b.Grow(9)
+		for i := 0; i < len(rtn); i++ {
+			if isDigitByte(rtn[i]) {
+				b.WriteByte(rtn[i])
+			}
+		}
+		rtn = b.String()
+	}
+
+	remainder, _ := ChecksumRemainder(rtn)
+	if remainder != 0 && !c.includeInvalid {
+		return matches
+	}
+
+	return append(matches, Match{Start: start, End: end, RTN: rtn, Valid: remainder == 0})
+}
+
+// isBoundaryBefore determines whether the text preceding the provided offset
+// ends a word or number.
+func isBoundaryBefore(s string, start int) bool {
+	r, size := utf8.DecodeLastRuneInString(s[:start])
+	if size == 0 {
+		return true
+	}
+	if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+		return false
+	}
+
+	// A decimal point or comma preceded by a digit continues a number
+	if r == '.' || r == ',' {
+		r, _ = utf8.DecodeLastRuneInString(s[:start-size])
+		return !unicode.IsDigit(r)
+	}
+
+	return true
+}
+
+// isBoundaryAfter determines whether the text following the provided offset
+// begins a new word or number.
+func isBoundaryAfter(s string, end int) bool {
+	r, size := utf8.DecodeRuneInString(s[end:])
+	if size == 0 {
+		return true
+	}
+	if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+		return false
+	}
+
+	// A decimal point or comma followed by a digit continues a number
+	if r == '.' || r == ',' {
+		r, _ = utf8.DecodeRuneInString(s[end+size:])
+		return !unicode.IsDigit(r)
+	}
+
+	return true
+}
+
+// countDigits counts the ASCII digits in the provided string.
+func countDigits(s string) (n int) {
+	for i := 0; i < len(s); i++ {
+		if isDigitByte(s[i]) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// isDigitByte determines whether the provided byte is an ASCII digit.
+func isDigitByte(c byte) bool {
+	return c >= '0' && c <= '9'
+}
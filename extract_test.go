@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Match
+	}{
+		{"", nil},
+		{"no numbers here", nil},
+		{"021200025", []Match{{0, 9, "021200025", true}}},
+		{"Routing: 021200025, account 12345.", []Match{{9, 18, "021200025", true}}},
+		{"use 021-200-025 or 0212 0002 5", []Match{
+			{4, 15, "021200025", true},
+			{19, 30, "021200025", true},
+		}},
+		{"RTN 322286188\nAcct 021200026", []Match{{4, 13, "322286188", true}}},
+		{"(⑆026014601⑆)", []Match{{4, 13, "026014601", true}}},
+
+		// Digit runs which are too long or too short
+		{"call 2125550199 today", nil},
+		{"call 212-555-0199-0 today", nil},
+		{"0212000025", nil},
+		{"02120002", nil},
+		{"021200025021200025", nil},
+
+		// Word boundaries
+		{"A021200025", nil},
+		{"021200025B", nil},
+		{"é021200025", nil},
+		{"_021200025", nil},
+		{"$021200025.00", nil},
+		{"1,021200025", nil},
+		{"021200025. Next", []Match{{0, 9, "021200025", true}}},
+		{"021200025,", []Match{{0, 9, "021200025", true}}},
+
+		// Separators
+		{"021--200025", nil},
+		{"021  200025", nil},
+		{"021200025-", []Match{{0, 9, "021200025", true}}},
+		{"555 021200025", []Match{{4, 13, "021200025", true}}},
+		{"021 200 025 1234 322286188", []Match{{17, 26, "322286188", true}}},
+	}
+
+	for _, test := range tests {
+		actual := Extract(test.input)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf(
+				"input %q generated actual output %+v (expected %+v)",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestExtractIncludeInvalid(t *testing.T) {
+	actual := Extract("valid 021200025, typo 021200026, long 0212000250", IncludeInvalid())
+	expected := []Match{
+		{6, 15, "021200025", true},
+		{22, 31, "021200026", false},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("input generated actual output %+v (expected %+v)", actual, expected)
+	}
+}
+
+func TestExtractAllocations(t *testing.T) {
+	var (
+		body    = strings.Repeat("Please call 212-555-0199 about invoice 20240115, thanks. ", 1000)
+		matched = body + "Routing number 021200025."
+	)
+
+	if allocs := testing.AllocsPerRun(10, func() { Extract(body) }); allocs != 0 {
+		t.Fatalf("text without candidates generated %.0f allocations (expected 0)", allocs)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { Extract(matched) }); allocs != 1 {
+		t.Fatalf("text with one candidate generated %.0f allocations (expected 1)", allocs)
+	}
+}
+
+func BenchmarkExtract(b *testing.B) {
+	body := strings.Repeat("Please call 212-555-0199 about invoice 20240115, thanks. ", 1000) +
+		"Routing number 021-200-025."
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	for i := 0; i < b.N; i++ {
+		Extract(body)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// fedACHRecordLength is the length of each record of the FedACH participant
+// directory.
+const fedACHRecordLength = 155
+
+// ACHParticipant is a record of the FedACH participant directory, describing
+// an institution which receives ACH entries.
+type ACHParticipant struct {
+	RoutingNumber RTN
+
+	// HeadOffice is true for main offices and false for branches
+	HeadOffice bool
+
+	// ServicingFRBNumber is the routing number of the servicing Federal
+	// Reserve Bank office
+	ServicingFRBNumber string
+
+	// RecordType is 0 for records of Federal Reserve Banks, 1 when entries are
+	// sent to RoutingNumber, and 2 when entries are sent to NewRoutingNumber
+	RecordType int
+
+	// ChangeDate is the date the record last changed, or the zero time if it
+	// is not provided
+	ChangeDate time.Time
+
+	// NewRoutingNumber is the routing number which replaced RoutingNumber, or
+	// the zero value if it has not been replaced
+	NewRoutingNumber RTN
+
+	CustomerName          string
+	Address               string
+	City                  string
+	State                 string
+	ZipCode               string
+	ZipCodeExtension      string
+	PhoneNumber           string
+	InstitutionStatusCode string
+	DataViewCode          string
+}
+
+// ParseFedACHDirectory decodes the FedACH participant directory (FedACHdir.txt)
+// published by the Federal Reserve, in which each line is a 155-character
+// fixed-width record. Text fields are trimmed of padding, and the routing
+// number fields are validated as Parse does. Blank lines are skipped.
+//
+// Decoding ends at the first record which can't be decoded, returning a
+// *RecordError which identifies its line and wraps either ErrMalformedRecord or
+// the error produced by validation, along with the records decoded before it.
+// CollectErrors causes such records to be skipped instead.
+func ParseFedACHDirectory(r io.Reader, opts ...DirectoryOption) (participants []ACHParticipant, err error) {
+	err = decodeRecords(r, fedACHRecordLength, opts, func(record string) (err error) {
+		var p ACHParticipant
+
+		if p.RoutingNumber, err = recordRTN(fixedField(record, 1, 9), "routing number", false); err != nil {
+			return err
+		}
+
+		switch office := record[9]; office {
+		case 'O':
+			p.HeadOffice = true
+		case 'B':
+		default:
+			return &RecordError{
+				Field: "office code",
+				Err:   fmt.Errorf("%w: invalid office code %q", ErrMalformedRecord, office),
+			}
+		}
+
+		p.ServicingFRBNumber = fixedField(record, 11, 19)
+
+		switch recordType := record[19]; recordType {
+		case '0', '1', '2':
+			p.RecordType = int(recordType - '0')
+		default:
+			return &RecordError{
+				Field: "record type code",
+				Err:   fmt.Errorf("%w: invalid record type code %q", ErrMalformedRecord, recordType),
+			}
+		}
+
+		if p.ChangeDate, err = recordDate(fixedField(record, 21, 26), "010206", "change date"); err != nil {
+			return err
+		}
+		if p.NewRoutingNumber, err = recordRTN(fixedField(record, 27, 35), "new routing number", true); err != nil {
+			return err
+		}
+
+		p.CustomerName = fixedField(record, 36, 71)
+		p.Address = fixedField(record, 72, 107)
+		p.City = fixedField(record, 108, 127)
+		p.State = fixedField(record, 128, 129)
+		p.ZipCode = fixedField(record, 130, 134)
+		p.ZipCodeExtension = fixedField(record, 135, 138)
+		p.PhoneNumber = fixedField(record, 139, 148)
+		p.InstitutionStatusCode = fixedField(record, 149, 149)
+		p.DataViewCode = fixedField(record, 150, 150)
+
+		participants = append(participants, p)
+		return nil
+	})
+
+	return participants, err
+}
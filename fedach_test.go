@@ -0,0 +1,137 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fedACHRecord builds a FedACH directory record by replacing the columns of a
+// valid record beginning at the provided one-based column.
+func fedACHRecord(column int, replacement string) string {
+	const valid = "021200025O0210012081072811000000000EXAMPLE NATIONAL BANK               " +
+		"100 MAIN STREET                     NEWARK              NJ071020000973555010011     "
+
+	return valid[:column-1] + replacement + valid[column-1+len(replacement):]
+}
+
+func TestParseFedACHDirectory(t *testing.T) {
+	f, err := os.Open("testdata/FedACHdir.txt")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %s", err)
+	}
+	defer f.Close()
+
+	participants, err := ParseFedACHDirectory(f)
+	if err != nil {
+		t.Fatalf("fixture generated actual error \"%s\"", err)
+	}
+	if len(participants) != 5 {
+		t.Fatalf("fixture generated %d participants (expected 5)", len(participants))
+	}
+
+	var (
+		newRTN, _ = Parse("111000025")
+		expected  = ACHParticipant{
+			RoutingNumber:         participants[3].RoutingNumber,
+			HeadOffice:            true,
+			ServicingFRBNumber:    "121000374",
+			RecordType:            2,
+			ChangeDate:            time.Date(2016, time.May, 5, 0, 0, 0, 0, time.UTC),
+			NewRoutingNumber:      newRTN,
+			CustomerName:          "EXAMPLE FEDERAL CREDIT UNION",
+			Address:               "PO BOX 1000",
+			City:                  "LOS ANGELES",
+			State:                 "CA",
+			ZipCode:               "90017",
+			ZipCodeExtension:      "",
+			PhoneNumber:           "2135550142",
+			InstitutionStatusCode: "1",
+			DataViewCode:          "1",
+		}
+	)
+	if participants[3] != expected || participants[3].RoutingNumber.String() != "322286188" {
+		t.Fatalf("fixture generated actual participant %+v (expected %+v)", participants[3], expected)
+	}
+
+	// Branches, leading zeros, absent successors, and blank dates
+	branch := participants[2]
+	if branch.HeadOffice || branch.RoutingNumber.String() != "026014601" || !branch.NewRoutingNumber.IsZero() {
+		t.Fatalf("fixture generated actual branch participant %+v", branch)
+	}
+	if !participants[4].ChangeDate.IsZero() {
+		t.Fatalf("blank change date generated actual date %s", participants[4].ChangeDate)
+	}
+}
+
+func TestParseFedACHDirectoryErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedLine  int
+		expectedField string
+		expectedError error
+	}{
+		{fedACHRecord(1, "021200026"), 1, "routing number", ErrChecksumMismatch},
+		{fedACHRecord(1, "02120002O"), 1, "routing number", ErrInvalidCharacter},
+		{fedACHRecord(10, "X"), 1, "office code", ErrMalformedRecord},
+		{fedACHRecord(20, "3"), 1, "record type code", ErrMalformedRecord},
+		{fedACHRecord(21, "133011"), 1, "change date", ErrMalformedRecord},
+		{fedACHRecord(27, "123456789"), 1, "new routing number", ErrChecksumMismatch},
+		{fedACHRecord(1, "021200025") + "\n" + fedACHRecord(1, "026014601")[:150], 2, "", ErrMalformedRecord},
+		{"\n\n" + fedACHRecord(1, "021200025") + "X", 3, "", ErrMalformedRecord},
+	}
+
+	for _, test := range tests {
+		participants, err := ParseFedACHDirectory(strings.NewReader(test.input))
+
+		var recordErr *RecordError
+		if !errors.As(err, &recordErr) ||
+			recordErr.Line != test.expectedLine ||
+			recordErr.Field != test.expectedField ||
+			!errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input %q generated actual output %v, \"%v\" (expected line %d, field \"%s\", \"%v\")",
+				test.input,
+				participants,
+				err,
+				test.expectedLine,
+				test.expectedField,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestParseFedACHDirectoryCollectErrors(t *testing.T) {
+	input := strings.Join(
+		[]string{
+			fedACHRecord(1, "021200025"),
+			fedACHRecord(1, "021200026"),
+			fedACHRecord(1, "026014601"),
+			"too short",
+		},
+		"\n",
+	)
+
+	participants, err := ParseFedACHDirectory(strings.NewReader(input), CollectErrors())
+	if len(participants) != 2 || participants[1].RoutingNumber.String() != "026014601" {
+		t.Fatalf("collecting errors generated actual participants %v", participants)
+	}
+
+	var errs RecordErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Line != 2 || errs[1].Line != 4 {
+		t.Fatalf("collecting errors generated actual error \"%v\"", err)
+	}
+	if !strings.HasPrefix(err.Error(), "line 2: routing number: ") || !strings.HasSuffix(err.Error(), " (and 1 more)") {
+		t.Fatalf("collecting errors generated actual message \"%s\"", err)
+	}
+	if !errors.Is(errs[0], ErrChecksumMismatch) || !errors.Is(errs[1], ErrMalformedRecord) {
+		t.Fatalf("collecting errors generated actual errors \"%v\", \"%v\"", errs[0], errs[1])
+	}
+}
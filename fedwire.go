@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// fedwireRecordLength is the length of each record of the Fedwire participant
+// directory.
+const fedwireRecordLength = 101
+
+// FedwireParticipant is a record of the Fedwire participant directory,
+// describing an institution which is eligible to send and receive Fedwire
+// transfers.
+type FedwireParticipant struct {
+	RoutingNumber   RTN
+	TelegraphicName string
+	CustomerName    string
+	State           string
+	City            string
+
+	// FundsTransfer is true if the institution is eligible for Fedwire funds
+	// transfers
+	FundsTransfer bool
+
+	// SettlementOnly is true if the institution may only settle funds
+	// transfers
+	SettlementOnly bool
+
+	// SecuritiesTransfer is true if the institution is eligible for Fedwire
+	// securities transfers
+	SecuritiesTransfer bool
+
+	// RevisionDate is the date the record was last revised, or the zero time
+	// if it is not provided
+	RevisionDate time.Time
+}
+
+// ParseFedwireDirectory decodes the Fedwire participant directory (fpddir.txt)
+// published by the Federal Reserve, in which each line is a 101-character
+// fixed-width record. It reports errors as ParseFedACHDirectory does.
+func ParseFedwireDirectory(r io.Reader, opts ...DirectoryOption) (participants []FedwireParticipant, err error) {
+	err = decodeRecords(r, fedwireRecordLength, opts, func(record string) (err error) {
+		var p FedwireParticipant
+
+		if p.RoutingNumber, err = recordRTN(fixedField(record, 1, 9), "routing number", false); err != nil {
+			return err
+		}
+
+		p.TelegraphicName = fixedField(record, 10, 27)
+		p.CustomerName = fixedField(record, 28, 63)
+		p.State = fixedField(record, 64, 65)
+		p.City = fixedField(record, 66, 90)
+
+		if p.FundsTransfer, err = recordFlag(record[90], 'Y', 'N', "funds transfer status"); err != nil {
+			return err
+		}
+		if p.SettlementOnly, err = recordFlag(record[91], 'S', ' ', "settlement-only status"); err != nil {
+			return err
+		}
+		if p.SecuritiesTransfer, err = recordFlag(record[92], 'Y', 'N', "securities transfer status"); err != nil {
+			return err
+		}
+
+		if p.RevisionDate, err = recordDate(fixedField(record, 94, 101), "20060102", "revision date"); err != nil {
+			return err
+		}
+
+		participants = append(participants, p)
+		return nil
+	})
+
+	return participants, err
+}
+
+// recordFlag decodes the provided directory field as a boolean.
+func recordFlag(value, yes, no byte, field string) (flag bool, err error) {
+	switch value {
+	case yes:
+		return true, nil
+	case no:
+		return false, nil
+	}
+
+	return false, &RecordError{
+		Field: field,
+		Err:   fmt.Errorf("%w: invalid flag %q", ErrMalformedRecord, value),
+	}
+}
+
+// FedwireDirectory indexes the records of the Fedwire participant directory by
+// routing number. It is safe for concurrent lookups.
+type FedwireDirectory struct {
+	index map[string]FedwireParticipant
+}
+
+// NewFedwireDirectory builds a directory from the provided participants. If
+// more than one participant has the same routing number, the first is kept.
+func NewFedwireDirectory(participants []FedwireParticipant) *FedwireDirectory {
+	d := &FedwireDirectory{index: make(map[string]FedwireParticipant, len(participants))}
+	for _, p := range participants {
+		if _, ok := d.index[p.RoutingNumber.String()]; !ok {
+			d.index[p.RoutingNumber.String()] = p
+		}
+	}
+
+	return d
+}
+
+// Lookup returns the participant with the provided routing number, if any.
+// Participants are included whether or not they are eligible for funds
+// transfers; check FundsTransfer and SecuritiesTransfer before routing a wire.
+func (d *FedwireDirectory) Lookup(rtn string) (participant FedwireParticipant, ok bool) {
+	participant, ok = d.index[rtn]
+	return participant, ok
+}
+
+// IsWireEligible determines whether the provided RTN is valid and belongs to a
+// participant in the directory which is eligible for Fedwire funds transfers.
+// Settlement-only participants are eligible.
+func (d *FedwireDirectory) IsWireEligible(rtn string) bool {
+	if !isValid(rtn) {
+		return false
+	}
+
+	p, ok := d.Lookup(rtn)
+
+	return ok && p.FundsTransfer
+}
+
+// Len returns the number of participants in the directory.
+func (d *FedwireDirectory) Len() int {
+	return len(d.index)
+}
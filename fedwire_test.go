@@ -0,0 +1,185 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fedwireRecord builds a Fedwire directory record by replacing the columns of a
+// valid record beginning at the provided one-based column.
+func fedwireRecord(column int, replacement string) string {
+	const valid = "021200025EXAMPLE NB        EXAMPLE NATIONAL BANK               " +
+		"NJNEWARK                   Y N20190715"
+
+	return valid[:column-1] + replacement + valid[column-1+len(replacement):]
+}
+
+func TestParseFedwireDirectory(t *testing.T) {
+	f, err := os.Open("testdata/fpddir.txt")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %s", err)
+	}
+	defer f.Close()
+
+	// The fourth record of the fixture has a corrupted routing number
+	participants, err := ParseFedwireDirectory(f, CollectErrors())
+
+	var errs RecordErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Line != 4 || !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("fixture generated actual error \"%v\"", err)
+	}
+	if len(participants) != 4 {
+		t.Fatalf("fixture generated %d participants (expected 4)", len(participants))
+	}
+
+	expected := FedwireParticipant{
+		RoutingNumber:      participants[1].RoutingNumber,
+		TelegraphicName:    "EXAMPLE NB",
+		CustomerName:       "EXAMPLE NATIONAL BANK",
+		State:              "NJ",
+		City:               "NEWARK",
+		FundsTransfer:      true,
+		SettlementOnly:     false,
+		SecuritiesTransfer: false,
+		RevisionDate:       time.Date(2019, time.July, 15, 0, 0, 0, 0, time.UTC),
+	}
+	if participants[1] != expected || participants[1].RoutingNumber.String() != "021200025" {
+		t.Fatalf("fixture generated actual participant %+v (expected %+v)", participants[1], expected)
+	}
+
+	// Settlement-only status and blank revision dates
+	if !participants[2].SettlementOnly || !participants[2].RevisionDate.IsZero() {
+		t.Fatalf("fixture generated actual participant %+v", participants[2])
+	}
+	if participants[3].RoutingNumber.String() != "322286188" || participants[3].FundsTransfer {
+		t.Fatalf("fixture generated actual participant %+v", participants[3])
+	}
+}
+
+func TestParseFedwireDirectoryErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedLine  int
+		expectedField string
+		expectedError error
+	}{
+		{fedwireRecord(1, "123456789"), 1, "routing number", ErrChecksumMismatch},
+		{fedwireRecord(91, "X"), 1, "funds transfer status", ErrMalformedRecord},
+		{fedwireRecord(92, "Y"), 1, "settlement-only status", ErrMalformedRecord},
+		{fedwireRecord(93, " "), 1, "securities transfer status", ErrMalformedRecord},
+		{fedwireRecord(94, "20190230"), 1, "revision date", ErrMalformedRecord},
+		{fedwireRecord(94, "2019071X"), 1, "revision date", ErrMalformedRecord},
+		{fedwireRecord(1, "021200025") + "\n" + fedwireRecord(1, "021200025")[:100], 2, "", ErrMalformedRecord},
+	}
+
+	for _, test := range tests {
+		_, err := ParseFedwireDirectory(strings.NewReader(test.input))
+
+		var recordErr *RecordError
+		if !errors.As(err, &recordErr) ||
+			recordErr.Line != test.expectedLine ||
+			recordErr.Field != test.expectedField ||
+			!errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input %q generated actual error \"%v\" (expected line %d, field \"%s\", \"%v\")",
+				test.input,
+				err,
+				test.expectedLine,
+				test.expectedField,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestFedwireDirectoryLookup(t *testing.T) {
+	f, err := os.Open("testdata/fpddir.txt")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %s", err)
+	}
+	defer f.Close()
+
+	participants, _ := ParseFedwireDirectory(f, CollectErrors())
+	d := NewFedwireDirectory(participants)
+
+	if d.Len() != 4 {
+		t.Fatalf("fixture generated a directory of %d participants (expected 4)", d.Len())
+	}
+
+	p, ok := d.Lookup("021200025")
+	if !ok || p.TelegraphicName != "EXAMPLE NB" || p.CustomerName != "EXAMPLE NATIONAL BANK" || !p.FundsTransfer {
+		t.Fatalf("lookup generated actual output %+v, %t", p, ok)
+	}
+
+	if p, ok = d.Lookup("322286188"); !ok || p.FundsTransfer || p.SecuritiesTransfer {
+		t.Fatalf("lookup generated actual output %+v, %t", p, ok)
+	}
+
+	// The corrupted record is skipped
+	if _, ok = d.Lookup("123456789"); ok {
+		t.Fatalf("lookup of a corrupted routing number succeeded")
+	}
+}
+
+func TestNewFedwireDirectoryDuplicates(t *testing.T) {
+	rtn, _ := Parse("021200025")
+	d := NewFedwireDirectory([]FedwireParticipant{
+		{RoutingNumber: rtn, TelegraphicName: "FIRST"},
+		{RoutingNumber: rtn, TelegraphicName: "SECOND"},
+	})
+
+	if p, _ := d.Lookup("021200025"); d.Len() != 1 || p.TelegraphicName != "FIRST" {
+		t.Fatalf("duplicates generated actual directory of %d participants, %+v", d.Len(), p)
+	}
+}
+
+func TestFedwireDirectoryIsWireEligible(t *testing.T) {
+	f, err := os.Open("testdata/fpddir.txt")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %s", err)
+	}
+	defer f.Close()
+
+	participants, _ := ParseFedwireDirectory(f, CollectErrors())
+	d := NewFedwireDirectory(participants)
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"011000015", true},
+		{"021200025", true},
+
+		// Settlement-only participants are eligible
+		{"026014601", true},
+
+		// Not eligible for funds transfers
+		{"322286188", false},
+
+		// Valid, but not a participant
+		{"044000037", false},
+
+		{"123456789", false},
+		{"02120002", false},
+	}
+
+	var actual bool
+	for _, test := range tests {
+		actual = d.IsWireEligible(test.input)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%s\" generated actual output %t (expected %t)",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrUnexpectedStatus indicates that a server responded to a request for a
+// participant directory with a status other than 200 OK or 304 Not Modified.
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+// defaultFetchTimeout bounds requests made with the default HTTP client.
+const defaultFetchTimeout = 2 * time.Minute
+
+// defaultFetchClient is used when no HTTP client is provided.
+var defaultFetchClient = &http.Client{Timeout: defaultFetchTimeout}
+
+// DirectoryCache holds a previously fetched participant directory along with
+// the validators needed to make conditional requests for it.
+type DirectoryCache struct {
+	ETag         string
+	LastModified string
+	Participants []ACHParticipant
+}
+
+// FetchFedACHDirectory downloads and decodes the FedACH participant directory
+// from the provided URL. The response body is decoded as it is read, as
+// ParseFedACHDirectory does with the provided options. A nil client uses a
+// default client with a timeout of 2 minutes.
+//
+// If a cache is provided, its validators are sent as If-None-Match and
+// If-Modified-Since headers. A 304 Not Modified response returns the cached
+// participants without decoding anything, and a successfully decoded 200 OK
+// response replaces the cache's contents. Any other status produces an error
+// wrapping ErrUnexpectedStatus which includes the status code.
+func FetchFedACHDirectory(
+	ctx context.Context,
+	client *http.Client,
+	url string,
+	cached *DirectoryCache,
+	opts ...DirectoryOption,
+) (participants []ACHParticipant, err error) {
+	participants, _, err = fetchFedACHDirectory(ctx, client, url, cached, nil, opts)
+	return participants, err
+}
+
+// fetchFedACHDirectory implements FetchFedACHDirectory, additionally reporting
+// whether the directory was modified and copying the body of a 200 OK response
+// to the provided writer, if any, as it is decoded.
+func fetchFedACHDirectory(
+	ctx context.Context,
+	client *http.Client,
+	url string,
+	cached *DirectoryCache,
+	w io.Writer,
+	opts []DirectoryOption,
+) (participants []ACHParticipant, modified bool, err error) {
+	if client == nil {
+		client = defaultFetchClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return cached.Participants, false, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	var body io.Reader = resp.Body
+	if w != nil {
+		body = io.TeeReader(resp.Body, w)
+	}
+
+	if participants, err = ParseFedACHDirectory(body, opts...); err != nil {
+		return participants, false, err
+	}
+
+	if cached != nil {
+		*cached = DirectoryCache{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Participants: participants,
+		}
+	}
+
+	return participants, true, nil
+}
+
+// Names of the files persisted within the cache directory of a DirectoryClient.
+const (
+	fedACHCacheFile      = "FedACHdir.txt"
+	fedACHValidatorsFile = "FedACHdir.json"
+)
+
+// directoryValidators holds the validators of a persisted directory, used to
+// make conditional requests for it.
+type directoryValidators struct {
+	ETag         string `json:"etag"`
+	LastModified string `json:"last_modified"`
+}
+
+// DirectoryClient keeps a Directory current with the FedACH participant
+// directory published at a URL. Each call to Refresh makes a conditional
+// request, reloading the directory only when it has changed. It is safe for
+// concurrent use, and lookups in its directory are never blocked by a refresh.
+type DirectoryClient struct {
+	// URL is the location of the FedACH participant directory.
+	URL string
+
+	// Client is used to make requests. If nil, a default client with a
+	// timeout of 2 minutes is used.
+	Client *http.Client
+
+	// CacheDir, if set, is a directory in which the most recently downloaded
+	// directory and its validators are kept, so that a restarted process can
+	// serve lookups and make conditional requests before downloading anything.
+	// The directory must already exist.
+	CacheDir string
+
+	mu        sync.Mutex
+	started   bool
+	cache     DirectoryCache
+	directory Directory
+}
+
+// Directory returns the directory kept current by the client. It is empty
+// until the first successful call to Refresh.
+func (c *DirectoryClient) Directory() *Directory {
+	return &c.directory
+}
+
+// Lookup returns the participant with the provided routing number, if any.
+func (c *DirectoryClient) Lookup(rtn string) (participant ACHParticipant, ok bool) {
+	return c.directory.Lookup(rtn)
+}
+
+// Search returns the participants matching the provided query, as
+// Directory.Search does.
+func (c *DirectoryClient) Search(q Query) (participants []ACHParticipant) {
+	return c.directory.Search(q)
+}
+
+// Refresh requests the directory, reloading it if it has changed since the
+// last successful refresh, and reports whether it was reloaded. The first call
+// also loads the directory persisted in CacheDir, if any, which is reported as
+// a change even if the request then fails. If the request or decoding fails,
+// the directory is left unchanged. If a reloaded directory can't be persisted
+// to CacheDir, it is still reloaded and the error is returned.
+func (c *DirectoryClient) Refresh(ctx context.Context) (changed bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.started {
+		c.started = true
+		changed = c.loadCache()
+	}
+
+	var (
+		tmp *os.File
+		w   io.Writer
+	)
+	if c.CacheDir != "" {
+		if tmp, err = os.CreateTemp(c.CacheDir, fedACHCacheFile+".*"); err != nil {
+			return changed, err
+		}
+		defer func() {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}()
+		w = tmp
+	}
+
+	participants, modified, err := fetchFedACHDirectory(ctx, c.Client, c.URL, &c.cache, w, nil)
+	if err != nil || !modified {
+		return changed, err
+	}
+
+	// The directory holds the participants, so the cache only needs to keep
+	// the validators
+	c.cache.Participants = nil
+	c.directory.Reload(participants)
+
+	if tmp != nil {
+		err = c.saveCache(tmp)
+	}
+
+	return true, err
+}
+
+// loadCache loads the directory and validators persisted in the cache
+// directory, if any, reporting whether the directory was loaded. A persisted
+// directory which can't be decoded is ignored, so that it is downloaded again.
+func (c *DirectoryClient) loadCache() (loaded bool) {
+	if c.CacheDir == "" {
+		return false
+	}
+
+	f, err := os.Open(filepath.Join(c.CacheDir, fedACHCacheFile))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	participants, err := ParseFedACHDirectory(f)
+	if err != nil {
+		return false
+	}
+	c.directory.Reload(participants)
+
+	var v directoryValidators
+	if data, err := os.ReadFile(filepath.Join(c.CacheDir, fedACHValidatorsFile)); err == nil {
+		if err = json.Unmarshal(data, &v); err == nil {
+			c.cache.ETag, c.cache.LastModified = v.ETag, v.LastModified
+		}
+	}
+
+	return true
+}
+
+// saveCache persists the downloaded directory, written to the provided
+// temporary file, and its validators to the cache directory.
+func (c *DirectoryClient) saveCache(tmp *os.File) (err error) {
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp.Name(), filepath.Join(c.CacheDir, fedACHCacheFile)); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(directoryValidators{ETag: c.cache.ETag, LastModified: c.cache.LastModified})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(c.CacheDir, fedACHValidatorsFile), data, 0o644)
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newDirectoryServer serves the FedACH fixture with an ETag and Last-Modified
+// header, counting the full responses it sends.
+func newDirectoryServer(t *testing.T, served *int) *httptest.Server {
+	fixture, err := os.ReadFile("testdata/FedACHdir.txt")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %s", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		case "/malformed":
+			_, _ = w.Write([]byte("not a directory\n"))
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		*served++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2020 00:00:00 GMT")
+		_, _ = w.Write(fixture)
+	}))
+}
+
+func TestFetchFedACHDirectory(t *testing.T) {
+	var served int
+	server := newDirectoryServer(t, &served)
+	defer server.Close()
+
+	var cache DirectoryCache
+
+	// The first request populates the cache
+	participants, err := FetchFedACHDirectory(context.Background(), nil, server.URL, &cache)
+	if err != nil || len(participants) != 5 || served != 1 {
+		t.Fatalf("first fetch generated actual output %d participants, \"%v\"", len(participants), err)
+	}
+	if cache.ETag != `"v1"` || cache.LastModified == "" || len(cache.Participants) != 5 {
+		t.Fatalf("first fetch generated actual cache %+v", cache)
+	}
+
+	// The second request is answered with 304 Not Modified
+	participants, err = FetchFedACHDirectory(context.Background(), server.Client(), server.URL, &cache)
+	if err != nil || len(participants) != 5 || served != 1 {
+		t.Fatalf("second fetch generated actual output %d participants, \"%v\"", len(participants), err)
+	}
+
+	// Without a cache, the directory is always downloaded
+	participants, err = FetchFedACHDirectory(context.Background(), server.Client(), server.URL, nil)
+	if err != nil || len(participants) != 5 || served != 2 {
+		t.Fatalf("uncached fetch generated actual output %d participants, \"%v\"", len(participants), err)
+	}
+}
+
+func TestFetchFedACHDirectoryErrors(t *testing.T) {
+	var served int
+	server := newDirectoryServer(t, &served)
+	defer server.Close()
+
+	cache := DirectoryCache{ETag: `"v0"`}
+
+	_, err := FetchFedACHDirectory(context.Background(), nil, server.URL+"/missing", &cache)
+	if !errors.Is(err, ErrUnexpectedStatus) || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("missing directory generated actual error \"%v\"", err)
+	}
+
+	_, err = FetchFedACHDirectory(context.Background(), nil, server.URL+"/malformed", &cache)
+	if !errors.Is(err, ErrMalformedRecord) || cache.ETag != `"v0"` {
+		t.Fatalf("malformed directory generated actual error \"%v\" and cache %+v", err, cache)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = FetchFedACHDirectory(ctx, nil, server.URL, &cache)
+	if !errors.Is(err, context.Canceled) || served != 0 {
+		t.Fatalf("canceled fetch generated actual error \"%v\"", err)
+	}
+}
+
+func TestDirectoryClientRefresh(t *testing.T) {
+	var served int
+	server := newDirectoryServer(t, &served)
+	defer server.Close()
+
+	var (
+		ctx   = context.Background()
+		dir   = t.TempDir()
+		first = &DirectoryClient{URL: server.URL, Client: server.Client(), CacheDir: dir}
+	)
+
+	// The first refresh downloads and persists the directory
+	changed, err := first.Refresh(ctx)
+	if !changed || err != nil || served != 1 || first.Directory().Len() != 5 {
+		t.Fatalf("first refresh generated actual output %t, \"%v\"", changed, err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, fedACHCacheFile)); err != nil {
+		t.Fatalf("first refresh did not persist the directory: %s", err)
+	}
+
+	// The second refresh is answered with 304 Not Modified
+	changed, err = first.Refresh(ctx)
+	if changed || err != nil || served != 1 || first.Directory().Len() != 5 {
+		t.Fatalf("second refresh generated actual output %t, \"%v\"", changed, err)
+	}
+
+	// A new client loads the persisted directory and makes a conditional
+	// request with its validators
+	second := &DirectoryClient{URL: server.URL, Client: server.Client(), CacheDir: dir}
+	changed, err = second.Refresh(ctx)
+	if !changed || err != nil || served != 1 || second.Directory().Len() != 5 {
+		t.Fatalf("restarted refresh generated actual output %t, \"%v\"", changed, err)
+	}
+	if _, ok := second.Lookup("026014601"); !ok {
+		t.Fatalf("restarted refresh generated a directory without a persisted participant")
+	}
+
+	// Temporary files are not left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("cache directory contains %d entries, \"%v\" (expected 2)", len(entries), err)
+	}
+}
+
+func TestDirectoryClientRefreshErrors(t *testing.T) {
+	var served int
+	server := newDirectoryServer(t, &served)
+	defer server.Close()
+
+	var (
+		ctx    = context.Background()
+		client = &DirectoryClient{URL: server.URL}
+	)
+
+	if changed, err := client.Refresh(ctx); !changed || err != nil {
+		t.Fatalf("refresh generated actual output %t, \"%v\"", changed, err)
+	}
+	stats := client.Directory().Stats()
+
+	// Failed refreshes leave the directory unchanged
+	for _, path := range []string{"/missing", "/malformed"} {
+		client.URL = server.URL + path
+
+		changed, err := client.Refresh(ctx)
+		if changed || err == nil || client.Directory().Stats() != stats {
+			t.Fatalf("refresh of \"%s\" generated actual output %t, \"%v\"", path, changed, err)
+		}
+	}
+
+	// A persisted directory which can't be decoded is downloaded again
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, fedACHCacheFile), []byte("truncated\n"), 0o644); err != nil {
+		t.Fatalf("failed to write cache: %s", err)
+	}
+
+	client = &DirectoryClient{URL: server.URL, CacheDir: dir}
+	if changed, err := client.Refresh(ctx); !changed || err != nil || client.Directory().Len() != 5 || served != 2 {
+		t.Fatalf("refresh with a corrupted cache generated actual output %t, \"%v\"", changed, err)
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FileIssue describes an invalid RTN found by ValidateFile.
+type FileIssue struct {
+	Record int    // Record is the one-based line or row number
+	Value  string // Value is the raw value which was validated
+	Err    error
+}
+
+// fileConfig holds the configuration built from a set of FileOptions.
+type fileConfig struct {
+	format    FileFormat
+	redaction *RedactionPolicy
+}
+
+// FileOption configures the behavior of ValidateFile.
+type FileOption func(*fileConfig)
+
+// WithFileFormat causes input to be read in the provided format rather than
+// the format detected by SniffFormat.
+func WithFileFormat(f FileFormat) FileOption {
+	return func(c *fileConfig) {
+		c.format = f
+	}
+}
+
+// WithFileRedaction causes the value and error of each issue, and any error
+// returned, to be redacted under the provided policy, as
+// RedactionPolicy.Redact and RedactionPolicy.RedactError do, so that reports
+// built from them don't expose RTNs. Values are validated before they are
+// redacted.
+func WithFileRedaction(p RedactionPolicy) FileOption {
+	return func(c *fileConfig) {
+		c.redaction = &p
+	}
+}
+
+// ValidateFile validates every RTN in the provided input and returns the format
+// it was read in along with an issue for each RTN which is invalid. The format
+// is detected by SniffFormat, returning its errors if it can't be determined,
+// unless WithFileFormat is provided. Values are normalized as Normalize does,
+// and a single leading zero lost from 8-digit values is restored.
+//
+// Plain lines are validated as ValidateLines does, skipping blank lines. CSV
+// and TSV input is validated as ValidateCSVColumn does with the column detected
+// from the leading rows, and a first row whose value contains no digits is
+// treated as a header. In JSONL input, the key whose values are most often
+// valid RTNs among the leading objects is validated, and in fixed-width input,
+// the 9-character field at the offset most often holding a valid RTN is
+// validated. A key or offset which can't be selected produces the errors of
+// DetectRTNColumn, and records without it produce an issue with
+// ErrMissingColumn. An error reading or decoding the input is returned along
+// with the issues found before it.
+func ValidateFile(r io.Reader, opts ...FileOption) (format FileFormat, issues []FileIssue, err error) {
+	var c fileConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	format = c.format
+	if format == FileFormatUnknown {
+		format, r, err = SniffFormat(r)
+	}
+	if err == nil {
+		issues, err = validateFile(r, format)
+	}
+
+	if c.redaction != nil {
+		for i := range issues {
+			issues[i].Value = c.redaction.Redact(issues[i].Value)
+			issues[i].Err = c.redaction.RedactError("", issues[i].Err)
+		}
+		err = c.redaction.RedactError("", err)
+	}
+
+	return format, issues, err
+}
+
+// validateFile validates every RTN in the provided input, which is in the
+// provided format.
+func validateFile(r io.Reader, format FileFormat) (issues []FileIssue, err error) {
+	switch format {
+	case FileFormatLines:
+		_, err = ValidateLines(
+			r,
+			func(line int, rtn string, err error) bool {
+				if err != nil {
+					issues = append(issues, FileIssue{Record: line, Value: rtn, Err: err})
+				}
+				return true
+			},
+			SkipBlankLines(),
+			LooseLines(),
+		)
+		return issues, err
+
+	case FileFormatCSV, FileFormatTSV:
+		var comma = ','
+		if format == FileFormatTSV {
+			comma = '\t'
+		}
+
+		csvIssues, err := ValidateCSVColumn(r, -1, Loose(), withComma(comma))
+		for _, issue := range csvIssues {
+			if issue.Row == 1 && !strings.ContainsAny(issue.Value, "0123456789") {
+				continue
+			}
+			issues = append(issues, FileIssue{Record: issue.Row, Value: issue.Value, Err: issue.Err})
+		}
+		return issues, err
+
+	case FileFormatJSONL:
+		return validateRecords(r, jsonFields)
+
+	case FileFormatFixedWidth:
+		return validateRecords(r, fixedWidthFields)
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// jsonFields returns the string and number values of the provided JSON object
+// keyed by name.
+func jsonFields(line string) (fields map[string]string, err error) {
+	var (
+		decoder = json.NewDecoder(strings.NewReader(line))
+		object  map[string]interface{}
+	)
+
+	decoder.UseNumber()
+	if err = decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	fields = make(map[string]string, len(object))
+	for key, value := range object {
+		switch value := value.(type) {
+		case string:
+			fields[key] = value
+		case json.Number:
+			fields[key] = value.String()
+		}
+	}
+
+	return fields, nil
+}
+
+// fixedWidthFields returns each 9-character field of the provided record keyed
+// by its offset.
+func fixedWidthFields(line string) (fields map[string]string, err error) {
+	fields = make(map[string]string)
+	for i := 0; i+9 <= len(line); i++ {
+		fields[strconv.Itoa(i)] = line[i : i+9]
+	}
+
+	return fields, nil
+}
+
+// fileRecord is a non-blank line of input split into named fields.
+type fileRecord struct {
+	line   int
+	fields map[string]string
+}
+
+// validateRecords validates one field of each non-blank line of the provided
+// reader, using the provided function to split each line into named fields.
+// The field is selected from the leading lines as detectRecordKey does.
+func validateRecords(
+	r io.Reader,
+	split func(line string) (map[string]string, error),
+) (issues []FileIssue, err error) {
+	var (
+		scanner = bufio.NewScanner(r)
+		sample  []fileRecord
+		key     string
+		line    int
+		fields  map[string]string
+	)
+
+	// Validate a record once the key to validate is known
+	c := csvConfig{loose: true}
+	validate := func(rec fileRecord) {
+		value, ok := rec.fields[key]
+		if !ok {
+			issues = append(issues, FileIssue{Record: rec.line, Err: ErrMissingColumn})
+			return
+		}
+		if err := validateCSVValue(value, &c); err != nil {
+			issues = append(issues, FileIssue{Record: rec.line, Value: value, Err: err})
+		}
+	}
+
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		if fields, err = split(strings.TrimSuffix(scanner.Text(), "\r")); err != nil {
+			return issues, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if key != "" {
+			validate(fileRecord{line, fields})
+			continue
+		}
+
+		sample = append(sample, fileRecord{line, fields})
+		if len(sample) < defaultSampleRows {
+			continue
+		}
+
+		if key, err = detectRecordKey(sample); err != nil {
+			return nil, err
+		}
+		for _, rec := range sample {
+			validate(rec)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return issues, err
+	}
+
+	if key == "" && len(sample) > 0 {
+		if key, err = detectRecordKey(sample); err != nil {
+			return nil, err
+		}
+		for _, rec := range sample {
+			validate(rec)
+		}
+	}
+
+	return issues, nil
+}
+
+// detectRecordKey returns the key of the field which is most often a valid RTN
+// in the provided records, validated as ValidateFile validates values. If no
+// key is valid in at least half of the records, ErrRTNColumnNotFound is
+// returned, and if the best score is shared by more than one key,
+// ErrAmbiguousRTNColumn is returned.
+func detectRecordKey(sample []fileRecord) (key string, err error) {
+	var (
+		c     = csvConfig{loose: true}
+		valid = make(map[string]int)
+	)
+	for _, rec := range sample {
+		for k, value := range rec.fields {
+			if validateCSVValue(value, &c) == nil {
+				valid[k]++
+			}
+		}
+	}
+
+	var (
+		best int
+		tied bool
+	)
+	for k, n := range valid {
+		switch {
+		case n > best:
+			key, best, tied = k, n, false
+		case n == best:
+			tied = true
+		}
+	}
+
+	if float64(best)/float64(len(sample)) < minColumnConfidence {
+		return "", ErrRTNColumnNotFound
+	}
+	if tied {
+		return "", ErrAmbiguousRTNColumn
+	}
+
+	return key, nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestValidateFile(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		opts           []FileOption
+		expectedFormat FileFormat
+		expectedIssues []FileIssue
+		expectedError  error
+	}{
+		{
+			"lines",
+			"322286188\n\n0212-0002-5\n123456789\n",
+			nil,
+			FileFormatLines,
+			[]FileIssue{{4, "123456789", ErrChecksumMismatch}},
+			nil,
+		},
+		{
+			"csv",
+			"name,routing\nAlice,021200025\nBob,21200025\nErin,322286188\nFrank,111000025\nGrace,026014601\nCarol,021200026\nDave,\n",
+			nil,
+			FileFormatCSV,
+			[]FileIssue{{7, "021200026", ErrChecksumMismatch}, {8, "", ErrIncorrectLength}},
+			nil,
+		},
+		{
+			"tsv",
+			"name\trouting\nAlice\t021200025\nBob\t322286188\nCarol\t02120O025\n",
+			nil,
+			FileFormatTSV,
+			[]FileIssue{{4, "02120O025", ErrInvalidCharacter}},
+			nil,
+		},
+		{
+			"jsonl",
+			"{\"id\":1,\"rtn\":\"021200025\"}\n{\"id\":2,\"rtn\":21200025}\n\n{\"id\":3,\"rtn\":\"123456789\"}\n{\"id\":4}\n",
+			nil,
+			FileFormatJSONL,
+			[]FileIssue{{4, "123456789", ErrChecksumMismatch}, {5, "", ErrMissingColumn}},
+			nil,
+		},
+		{
+			"fixed width",
+			"ALPHA BANK          322286188\n" +
+				"BETA BANK           021200025\n" +
+				"GAMMA BANK          123456789\n",
+			nil,
+			FileFormatFixedWidth,
+			[]FileIssue{{3, "123456789", ErrChecksumMismatch}},
+			nil,
+		},
+		{
+			"override",
+			"021200025,322286188\n",
+			[]FileOption{WithFileFormat(FileFormatLines)},
+			FileFormatLines,
+			[]FileIssue{{1, "021200025,322286188", ErrInvalidCharacter}},
+			nil,
+		},
+		{
+			"ambiguous format",
+			"021200025\nname,routing\n",
+			nil,
+			FileFormatUnknown,
+			nil,
+			ErrAmbiguousFormat,
+		},
+		{
+			"ambiguous key",
+			"{\"a\":\"021200025\",\"b\":\"322286188\"}\n",
+			nil,
+			FileFormatJSONL,
+			nil,
+			ErrAmbiguousRTNColumn,
+		},
+		{
+			"key not found",
+			"{\"a\":\"alpha\"}\n{\"a\":\"021200025\"}\n{\"a\":\"beta\"}\n",
+			nil,
+			FileFormatJSONL,
+			nil,
+			ErrRTNColumnNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		format, issues, err := ValidateFile(strings.NewReader(test.input), test.opts...)
+		if format != test.expectedFormat ||
+			!errors.Is(err, test.expectedError) ||
+			len(issues) != len(test.expectedIssues) {
+			t.Fatalf(
+				"test \"%s\" generated actual output \"%s\", %v, \"%v\" (expected \"%s\", %v, \"%v\")",
+				test.name,
+				format,
+				issues,
+				err,
+				test.expectedFormat,
+				test.expectedIssues,
+				test.expectedError,
+			)
+		}
+
+		for i, expected := range test.expectedIssues {
+			if issues[i].Record != expected.Record ||
+				issues[i].Value != expected.Value ||
+				!errors.Is(issues[i].Err, expected.Err) {
+				t.Fatalf(
+					"test \"%s\" generated actual issue %+v (expected %+v)",
+					test.name,
+					issues[i],
+					expected,
+				)
+			}
+		}
+	}
+}
+
+func TestValidateFileMalformed(t *testing.T) {
+	_, _, err := ValidateFile(strings.NewReader("{\"rtn\":\"021200025\"}\n{\"rtn\":\n"), WithFileFormat(FileFormatJSONL))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2: ") {
+		t.Fatalf("malformed input generated actual error \"%v\"", err)
+	}
+}
+
+func TestValidateFileRedaction(t *testing.T) {
+	inputs := []struct {
+		input string
+		opts  []FileOption
+	}{
+		{"322286188\n123456789\n02120O025\n", nil},
+		{"name,routing\nAlice,021200025\nBob,021200026\nCarol,322286188\n", nil},
+		{"{\"rtn\":\"021200025\"}\n{\"rtn\":\"021200026\"}\n{\"id\":7}\n", nil},
+		{"{\"rtn\":\"021200025\"}\n{\"rtn\":0212\n", []FileOption{WithFileFormat(FileFormatJSONL)}},
+		{"021200025\nname,routing\n", nil},
+	}
+
+	for _, input := range inputs {
+		opts := append([]FileOption{WithFileRedaction(RedactionPolicy{FullRedact: true})}, input.opts...)
+		_, issues, err := ValidateFile(strings.NewReader(input.input), opts...)
+
+		reports := []string{fmt.Sprint(err)}
+		for _, issue := range issues {
+			reports = append(reports, fmt.Sprintf("%s: %v", issue.Value, issue.Err))
+		}
+		for _, report := range reports {
+			if strings.IndexFunc(report, unicode.IsDigit) >= 0 {
+				t.Fatalf("input %q generated report containing digits \"%s\"", input.input, report)
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFraction indicates that a routing number in fraction form is
+// malformed, or that a fraction prefix is out of range.
+var ErrInvalidFraction = errors.New("invalid fraction")
+
+// ToFraction converts the provided RTN in MICR format into the fraction form
+// printed on checks, such as "1-2/210". The numerator is made up of the
+// provided city or state prefix followed by the ABA institution identifier,
+// and the denominator is the Federal Reserve routing symbol. Prefixes 1-49
+// identify cities and 50-99 identify states; since the prefix can't be derived
+// from the RTN, it must be provided by the caller. As printed on checks, the
+// leading zeros of the institution identifier and routing symbol are omitted.
+func ToFraction(rtn string, prefix int) (fraction string, err error) {
+	if err = Validate(rtn); err != nil {
+		return "", err
+	}
+
+	if prefix < 1 || prefix > 99 {
+		return "", ErrInvalidFraction
+	}
+
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(prefix))
+	b.WriteByte('-')
+	b.WriteString(trimLeadingZeros(rtn[4:8]))
+	b.WriteByte('/')
+	b.WriteString(trimLeadingZeros(rtn[:4]))
+
+	return b.String(), nil
+}
+
+// fractionPrefixes holds the places identified by the city and state prefixes
+// of the fraction form, indexed by prefix. Prefixes 1-49 identify cities and
+// 50-98 identify states. Prefixes 59 and 99, which don't identify a single
+// state, are omitted.
+var fractionPrefixes = [...]string{
+	1:  "New York, NY",
+	2:  "Chicago, IL",
+	3:  "Philadelphia, PA",
+	4:  "St. Louis, MO",
+	5:  "Boston, MA",
+	6:  "Cleveland, OH",
+	7:  "Baltimore, MD",
+	8:  "Pittsburgh, PA",
+	9:  "Detroit, MI",
+	10: "Buffalo, NY",
+	11: "San Francisco, CA",
+	12: "Milwaukee, WI",
+	13: "Cincinnati, OH",
+	14: "New Orleans, LA",
+	15: "Washington, DC",
+	16: "Los Angeles, CA",
+	17: "Minneapolis, MN",
+	18: "Kansas City, MO",
+	19: "Seattle, WA",
+	20: "Indianapolis, IN",
+	21: "Louisville, KY",
+	22: "St. Paul, MN",
+	23: "Denver, CO",
+	24: "Portland, OR",
+	25: "Columbus, OH",
+	26: "Memphis, TN",
+	27: "Omaha, NE",
+	28: "Spokane, WA",
+	29: "Albany, NY",
+	30: "San Antonio, TX",
+	31: "Salt Lake City, UT",
+	32: "Dallas, TX",
+	33: "Des Moines, IA",
+	34: "Tacoma, WA",
+	35: "Houston, TX",
+	36: "St. Joseph, MO",
+	37: "Fort Worth, TX",
+	38: "Savannah, GA",
+	39: "Oklahoma City, OK",
+	40: "Wichita, KS",
+	41: "Sioux City, IA",
+	42: "Pueblo, CO",
+	43: "Lincoln, NE",
+	44: "Topeka, KS",
+	45: "Dubuque, IA",
+	46: "Galveston, TX",
+	47: "Cedar Rapids, IA",
+	48: "Waco, TX",
+	49: "Muskogee, OK",
+	50: "New York",
+	51: "Connecticut",
+	52: "Maine",
+	53: "Massachusetts",
+	54: "New Hampshire",
+	55: "New Jersey",
+	56: "Ohio",
+	57: "Rhode Island",
+	58: "Vermont",
+	60: "Pennsylvania",
+	61: "Alabama",
+	62: "Delaware",
+	63: "Florida",
+	64: "Georgia",
+	65: "Maryland",
+	66: "North Carolina",
+	67: "South Carolina",
+	68: "Virginia",
+	69: "West Virginia",
+	70: "Illinois",
+	71: "Indiana",
+	72: "Iowa",
+	73: "Kentucky",
+	74: "Michigan",
+	75: "Minnesota",
+	76: "Nebraska",
+	77: "North Dakota",
+	78: "South Dakota",
+	79: "Wisconsin",
+	80: "Missouri",
+	81: "Arkansas",
+	82: "Colorado",
+	83: "Kansas",
+	84: "Louisiana",
+	85: "Mississippi",
+	86: "Oklahoma",
+	87: "Tennessee",
+	88: "Texas",
+	89: "Arizona",
+	90: "California",
+	91: "Idaho",
+	92: "Montana",
+	93: "Nevada",
+	94: "New Mexico",
+	95: "Oregon",
+	96: "Utah",
+	97: "Washington",
+	98: "Wyoming",
+}
+
+// FractionPrefix returns the city or state prefix code used in the numerator of
+// the fraction form for the provided place, ignoring letter case. Cities are
+// named along with their state's postal abbreviation, such as "Chicago, IL",
+// and states by their full name, such as "Illinois".
+func FractionPrefix(place string) (prefix int, ok bool) {
+	place = strings.TrimSpace(place)
+	for prefix = 1; prefix < len(fractionPrefixes); prefix++ {
+		if fractionPrefixes[prefix] != "" && strings.EqualFold(fractionPrefixes[prefix], place) {
+			return prefix, true
+		}
+	}
+
+	return 0, false
+}
+
+// FractionPlace returns the city or state identified by the provided prefix
+// code of the fraction form, named as FractionPrefix expects.
+func FractionPlace(prefix int) (place string, ok bool) {
+	if prefix < 1 || prefix >= len(fractionPrefixes) || fractionPrefixes[prefix] == "" {
+		return "", false
+	}
+
+	return fractionPrefixes[prefix], true
+}
+
+// FormatFraction converts the provided RTN in MICR format into fraction form,
+// as ToFraction does, using the prefix code for the provided city or state as
+// FractionPrefix returns it. A place without a prefix code produces
+// ErrInvalidFraction.
+func FormatFraction(rtn, place string) (fraction string, err error) {
+	if err = Validate(rtn); err != nil {
+		return "", err
+	}
+
+	prefix, ok := FractionPrefix(place)
+	if !ok {
+		return "", ErrInvalidFraction
+	}
+
+	return ToFraction(rtn, prefix)
+}
+
+// ParseFraction converts the provided routing number in fraction form, such as
+// "12-3456/1230", into a valid RTN in MICR format. The denominator is used as
+// the Federal Reserve routing symbol and the portion of the numerator following
+// the hyphen is used as the ABA institution identifier; both are zero-padded to
+// four digits, and the check digit is computed from the result. The city or
+// state prefix is validated but does not contribute to the RTN. Denominators
+// must be three or four digits, since routing symbols in districts 01-09 are
+// commonly printed without their leading zero.
+func ParseFraction(fraction string) (rtn string, err error) {
+	fraction = strings.TrimSpace(fraction)
+
+	slash := strings.IndexByte(fraction, '/')
+	if slash < 0 {
+		return "", ErrInvalidFraction
+	}
+	numerator, denominator := fraction[:slash], fraction[slash+1:]
+
+	hyphen := strings.IndexByte(numerator, '-')
+	if hyphen < 0 {
+		return "", ErrInvalidFraction
+	}
+	prefix, institution := numerator[:hyphen], numerator[hyphen+1:]
+
+	if !isDigits(prefix, 1, 2) || prefix == "0" || prefix == "00" ||
+		!isDigits(institution, 1, 4) ||
+		!isDigits(denominator, 3, 4) {
+		return "", ErrInvalidFraction
+	}
+
+	return Complete(padLeadingZeros(denominator, 4) + padLeadingZeros(institution, 4))
+}
+
+// isDigits determines whether the provided string is made up of between min
+// and max digits.
+func isDigits(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+
+	for _, r := range s {
+		if _, ok := runeToDigit(r); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// padLeadingZeros left-pads the provided string with zeros to the provided
+// length.
+func padLeadingZeros(s string, length int) string {
+	if len(s) >= length {
+		return s
+	}
+
+	return strings.Repeat("0", length-len(s)) + s
+}
+
+// trimLeadingZeros removes the leading zeros from the provided string of
+// digits, leaving at least one digit.
+func trimLeadingZeros(s string) string {
+	trimmed := strings.TrimLeft(s, "0")
+	if trimmed == "" {
+		return "0"
+	}
+
+	return trimmed
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToFraction(t *testing.T) {
+	tests := []struct {
+		rtn              string
+		prefix           int
+		expectedFraction string
+		expectedError    error
+	}{
+		{"1234", 1, "", ErrIncorrectLength},
+		{"R00000000", 1, "", ErrInvalidCharacter},
+		{"123456789", 1, "", ErrChecksumMismatch},
+		{"021000021", 0, "", ErrInvalidFraction},
+		{"021000021", 100, "", ErrInvalidFraction},
+		{"021000021", 1, "1-2/210", nil},
+		{"322286188", 90, "90-8618/3222", nil},
+		{"026014601", 1, "1-1460/260", nil},
+		{"111000025", 32, "32-2/1110", nil},
+		{"000000000", 50, "50-0/0", nil},
+	}
+
+	for _, test := range tests {
+		fraction, err := ToFraction(test.rtn, test.prefix)
+		if fraction != test.expectedFraction || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\", %d generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.rtn,
+				test.prefix,
+				fraction,
+				err,
+				test.expectedFraction,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestParseFraction(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedRTN   string
+		expectedError error
+	}{
+		{"1-2/210", "021000021", nil},
+		{"1-2/0210", "021000021", nil},
+		{" 90-8618/3222 ", "322286188", nil},
+		{"1-1460/260", "026014601", nil},
+		{"32-2/1110", "111000025", nil},
+		{"12-3456/1230", "123034568", nil},
+		{"1-2", "", ErrInvalidFraction},
+		{"1/210", "", ErrInvalidFraction},
+		{"-2/210", "", ErrInvalidFraction},
+		{"0-2/210", "", ErrInvalidFraction},
+		{"100-2/210", "", ErrInvalidFraction},
+		{"1-/210", "", ErrInvalidFraction},
+		{"1-12345/210", "", ErrInvalidFraction},
+		{"1-2/21", "", ErrInvalidFraction},
+		{"1-2/12345", "", ErrInvalidFraction},
+		{"1-2/21O", "", ErrInvalidFraction},
+		{"A-2/210", "", ErrInvalidFraction},
+		{"1-2-3/210", "", ErrInvalidFraction},
+		{"1-2/210/1", "", ErrInvalidFraction},
+	}
+
+	for _, test := range tests {
+		rtn, err := ParseFraction(test.input)
+		if rtn != test.expectedRTN || err != test.expectedError {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				rtn,
+				err,
+				test.expectedRTN,
+				test.expectedError,
+			)
+		}
+
+		if err == nil {
+			if err = Validate(rtn); err != nil {
+				t.Fatalf("parsed RTN \"%s\" failed validation: %s", rtn, err)
+			}
+		}
+	}
+}
+
+func TestFractionRoundTrip(t *testing.T) {
+	for _, rtn := range []string{"021000021", "322286188", "026014601", "111000025", "011000015"} {
+		fraction, err := ToFraction(rtn, 1)
+		if err != nil {
+			t.Fatalf("converting \"%s\" to a fraction failed: %s", rtn, err)
+		}
+
+		actual, err := ParseFraction(fraction)
+		if actual != rtn || err != nil {
+			t.Fatalf(
+				"fraction \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\")",
+				fraction,
+				actual,
+				err,
+				rtn,
+			)
+		}
+	}
+}
+
+func TestFormatFraction(t *testing.T) {
+	tests := []struct {
+		rtn              string
+		place            string
+		expectedFraction string
+		expectedError    error
+	}{
+		{"1234", "New York, NY", "", ErrIncorrectLength},
+		{"123456789", "New York, NY", "", ErrChecksumMismatch},
+		{"021000021", "Atlantis", "", ErrInvalidFraction},
+		{"021000021", "", "", ErrInvalidFraction},
+		{"021000021", "New York, NY", "1-2/210", nil},
+		{"021000021", " new york, ny ", "1-2/210", nil},
+		{"021000021", "New York", "50-2/210", nil},
+		{"111000025", "Dallas, TX", "32-2/1110", nil},
+		{"322286188", "California", "90-8618/3222", nil},
+		{"000000000", "Wyoming", "98-0/0", nil},
+	}
+
+	for _, test := range tests {
+		fraction, err := FormatFraction(test.rtn, test.place)
+		if fraction != test.expectedFraction || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\", \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.rtn,
+				test.place,
+				fraction,
+				err,
+				test.expectedFraction,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestFractionPrefixes(t *testing.T) {
+	// Every place must map back to its own prefix
+	var count int
+	for prefix := 0; prefix <= 100; prefix++ {
+		place, ok := FractionPlace(prefix)
+		if !ok {
+			continue
+		}
+		count++
+
+		if actual, ok := FractionPrefix(place); !ok || actual != prefix {
+			t.Fatalf("place \"%s\" generated actual prefix %d, %t (expected %d)", place, actual, ok, prefix)
+		}
+	}
+
+	if count != 97 {
+		t.Fatalf("generated %d places (expected 97)", count)
+	}
+
+	for _, prefix := range []int{0, 59, 99, 100} {
+		if place, ok := FractionPlace(prefix); ok {
+			t.Fatalf("prefix %d generated unexpected place \"%s\"", prefix, place)
+		}
+	}
+}
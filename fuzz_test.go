@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/schultz-is/rtnutil/internal/reference"
+)
+
+// referenceResult converts an error returned by Validate into the equivalent
+// reference result.
+func referenceResult(err error) reference.Result {
+	switch {
+	case err == nil:
+		return reference.Valid
+	case errors.Is(err, ErrIncorrectLength):
+		return reference.IncorrectLength
+	case errors.Is(err, ErrInvalidCharacter):
+		return reference.InvalidCharacter
+	case errors.Is(err, ErrChecksumMismatch):
+		return reference.ChecksumMismatch
+	}
+
+	return -1
+}
+
+func FuzzValidate(f *testing.F) {
+	seeds := []string{
+		"", "asdf", "0123456789", "R00000000", "123456789", "000000000",
+		"322286188", "021200025", "111000025", "026014601", "03110064X",
+		"02120002\xff", "0212000２5",
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		expected := reference.Validate(string(input))
+		actual := referenceResult(Validate(string(input)))
+		if actual != expected {
+			t.Fatalf(
+				"input %q generated actual result %d (reference %d)",
+				input,
+				actual,
+				expected,
+			)
+		}
+
+		actual = referenceResult(ValidateBytes(input))
+		if actual != expected {
+			t.Fatalf(
+				"input %q generated actual bytes result %d (reference %d)",
+				input,
+				actual,
+				expected,
+			)
+		}
+
+		actual = referenceResult(ValidateConstantTime(string(input)))
+		if actual != expected {
+			t.Fatalf(
+				"input %q generated actual constant-time result %d (reference %d)",
+				input,
+				actual,
+				expected,
+			)
+		}
+	})
+}
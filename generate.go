@@ -0,0 +1,337 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"strings"
+)
+
+// ErrInvalidDatasetFormat indicates that a dataset format is not recognized.
+var ErrInvalidDatasetFormat = errors.New("invalid dataset format")
+
+// ErrInvalidTableName indicates that a SQL table name is not a plain
+// identifier.
+var ErrInvalidTableName = errors.New("invalid table name")
+
+// ErrInvalidGenOption indicates that the options provided for generating RTNs
+// are out of range, exclude every prefix, or don't apply to the function they
+// were provided to.
+var ErrInvalidGenOption = errors.New("invalid generation option")
+
+// ErrInvalidCount indicates that a requested number of generated RTNs is
+// negative or exceeds the number which can be generated.
+var ErrInvalidCount = errors.New("invalid count")
+
+// DatasetFormat identifies an output format for GenerateDataset.
+type DatasetFormat int
+
+const (
+	// DatasetCSV writes a header row followed by one comma-separated row per
+	// record.
+	DatasetCSV DatasetFormat = iota
+
+	// DatasetJSONL writes one JSON object per line.
+	DatasetJSONL
+
+	// DatasetSQL writes one SQL INSERT statement per line.
+	DatasetSQL
+)
+
+// defaultTableName is the table populated by SQL datasets unless another is
+// configured.
+const defaultTableName = "routing_numbers"
+
+// genConfig holds the configuration built from a set of GenOptions.
+type genConfig struct {
+	seed      int64
+	tableName string
+	prefixLo  int
+	prefixHi  int
+	district  int
+
+	// datasetOnly is set by options which only apply to GenerateDataset
+	datasetOnly bool
+}
+
+// GenOption configures the generation of RTNs.
+type GenOption func(*genConfig)
+
+// WithSeed sets the seed used to generate RTNs for GenerateDataset. Output
+// generated with the same seed and options is identical. The default seed is
+// 1. Generate draws from the source it is provided instead, and rejects this
+// option.
+func WithSeed(seed int64) GenOption {
+	return func(c *genConfig) {
+		c.seed = seed
+		c.datasetOnly = true
+	}
+}
+
+// WithTableName sets the table populated by SQL datasets. The name must be
+// made up of letters, digits, underscores, and dots, and must not start with a
+// digit. The default table name is "routing_numbers". Generate rejects this
+// option.
+func WithTableName(name string) GenOption {
+	return func(c *genConfig) {
+		c.tableName = name
+		c.datasetOnly = true
+	}
+}
+
+// WithPrefixRange constrains the first two digits of generated RTNs to the
+// inclusive range [lo, hi], such as 1-12 for primary institutions or 61-72 for
+// electronic transactions. Both bounds must be between 0 and 99. The default
+// range is 1-12.
+func WithPrefixRange(lo, hi int) GenOption {
+	return func(c *genConfig) {
+		c.prefixLo, c.prefixHi = lo, hi
+	}
+}
+
+// WithFedDistrict constrains generated RTNs to prefixes which map to the
+// provided Federal Reserve district, from 1 to 12. When combined with
+// WithPrefixRange, only prefixes satisfying both are used; for example, the
+// default range restricts district 2 to the prefix 02, while the range 0-99
+// permits 02, 22, and 62.
+func WithFedDistrict(d int) GenOption {
+	return func(c *genConfig) {
+		c.district = d
+	}
+}
+
+// prefixes returns the set of two-digit prefixes permitted by the
+// configuration.
+func (c *genConfig) prefixes() (prefixes []int, err error) {
+	if c.prefixLo < 0 || c.prefixHi > 99 || c.prefixLo > c.prefixHi {
+		return nil, ErrInvalidGenOption
+	}
+	if c.district != 0 && (c.district < 1 || c.district > 12) {
+		return nil, ErrInvalidGenOption
+	}
+
+	for prefix := c.prefixLo; prefix <= c.prefixHi; prefix++ {
+		if c.district != 0 {
+			if number, ok := districtNumber(prefix); !ok || number != c.district {
+				continue
+			}
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	if len(prefixes) == 0 {
+		return nil, ErrInvalidGenOption
+	}
+
+	return prefixes, nil
+}
+
+// newGenConfig builds a configuration from the provided options.
+func newGenConfig(opts []GenOption) *genConfig {
+	c := &genConfig{
+		seed:      1,
+		tableName: defaultTableName,
+		prefixLo:  1,
+		prefixHi:  12,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// DatasetRecord is a single generated record written by GenerateDataset.
+type DatasetRecord struct {
+	RoutingNumber   string `json:"routing_number"`
+	InstitutionName string `json:"institution_name"`
+}
+
+// GenerateDataset writes n records containing unique, valid, synthetic RTNs
+// along with fake institution names to the provided writer in the requested
+// format. Institution names are derived from their RTNs, and the RTNs are
+// derived from the configured seed, so the output is reproducible. Generated
+// RTNs are constrained by WithPrefixRange and WithFedDistrict as they are for
+// Generate.
+func GenerateDataset(n int, format DatasetFormat, w io.Writer, opts ...GenOption) (err error) {
+	var c = newGenConfig(opts)
+
+	prefixes, err := c.prefixes()
+	if err != nil {
+		return err
+	}
+
+	// Each prefix is followed by 6 free digits
+	if n < 0 || n > len(prefixes)*1e6 {
+		return ErrInvalidCount
+	}
+
+	if format == DatasetSQL && !isSQLIdentifier(c.tableName) {
+		return ErrInvalidTableName
+	}
+
+	var (
+		r       = rand.New(rand.NewSource(c.seed))
+		seen    = make(map[string]struct{}, n)
+		bw      = bufio.NewWriter(w)
+		csvw    *csv.Writer
+		enc     *json.Encoder
+		record  DatasetRecord
+		written int
+	)
+
+	switch format {
+	case DatasetCSV:
+		csvw = csv.NewWriter(bw)
+		if err = csvw.Write([]string{"routing_number", "institution_name"}); err != nil {
+			return err
+		}
+	case DatasetJSONL:
+		enc = json.NewEncoder(bw)
+	case DatasetSQL:
+	default:
+		return ErrInvalidDatasetFormat
+	}
+
+	for written < n {
+		record.RoutingNumber = generateRTN(r.Intn, prefixes)
+		if _, ok := seen[record.RoutingNumber]; ok {
+			continue
+		}
+		seen[record.RoutingNumber] = struct{}{}
+		record.InstitutionName = fakeInstitutionName(record.RoutingNumber)
+
+		switch format {
+		case DatasetCSV:
+			err = csvw.Write([]string{record.RoutingNumber, record.InstitutionName})
+		case DatasetJSONL:
+			err = enc.Encode(record)
+		case DatasetSQL:
+			_, err = fmt.Fprintf(
+				bw,
+				"INSERT INTO %s (routing_number, institution_name) VALUES ('%s', '%s');\n",
+				c.tableName,
+				record.RoutingNumber,
+				strings.ReplaceAll(record.InstitutionName, "'", "''"),
+			)
+		}
+		if err != nil {
+			return err
+		}
+
+		written++
+	}
+
+	if csvw != nil {
+		csvw.Flush()
+		if err = csvw.Error(); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Generate produces a synthetic RTN which passes Validate, drawing randomness
+// from the provided source. If the source is nil, the math/rand package-level
+// source is used; callers which need reproducible output should provide a
+// seeded source. The first two digits are constrained by WithPrefixRange and
+// WithFedDistrict, and default to a primary institution prefix (01-12). The
+// first 8 digits are generated and the check digit is computed from them.
+// Options which don't constrain prefixes, such as WithSeed, produce
+// ErrInvalidGenOption rather than being ignored.
+func Generate(r *rand.Rand, opts ...GenOption) (rtn string, err error) {
+	var c = newGenConfig(opts)
+	if c.datasetOnly {
+		return "", ErrInvalidGenOption
+	}
+
+	prefixes, err := c.prefixes()
+	if err != nil {
+		return "", err
+	}
+
+	intn := rand.Intn
+	if r != nil {
+		intn = r.Intn
+	}
+
+	return generateRTN(intn, prefixes), nil
+}
+
+// generateRTN produces a valid RTN beginning with one of the provided prefixes
+// using the provided source of randomness.
+func generateRTN(intn func(int) int, prefixes []int) string {
+	var b strings.Builder
+	b.Grow(9)
+
+	prefix := prefixes[intn(len(prefixes))]
+	b.WriteByte(byte('0' + prefix/10))
+	b.WriteByte(byte('0' + prefix%10))
+
+	for i := 0; i < 6; i++ {
+		b.WriteByte(byte('0' + intn(10)))
+	}
+
+	// The prefix is made up of digits, so completing it cannot fail
+	rtn, _ := Complete(b.String())
+
+	return rtn
+}
+
+// Word lists used to synthesize fake institution names.
+var (
+	fakeNameFirst = []string{
+		"Amber", "Blue", "Cedar", "Copper", "Eagle", "Evergreen", "Granite", "Harbor",
+		"Heritage", "Iron", "Lakeside", "Liberty", "Maple", "Meadow", "Pioneer", "Prairie",
+		"River", "Silver", "Summit", "Valley",
+	}
+	fakeNameSecond = []string{
+		"Bay", "Bridge", "Canyon", "County", "Creek", "Crossing", "Falls", "Field",
+		"Hill", "Mountain", "Oak", "Point", "Ridge", "Rock", "Springs", "Trail",
+	}
+	fakeNameSuffix = []string{
+		"Bank", "Bank & Trust", "Credit Union", "Federal Savings", "National Bank",
+		"Savings Bank", "State Bank", "Trust Company",
+	}
+)
+
+// fakeInstitutionName deterministically synthesizes an institution name from
+// the provided RTN.
+func fakeInstitutionName(rtn string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(rtn))
+	sum := h.Sum32()
+
+	return fakeNameFirst[sum%uint32(len(fakeNameFirst))] + " " +
+		fakeNameSecond[(sum>>8)%uint32(len(fakeNameSecond))] + " " +
+		fakeNameSuffix[(sum>>16)%uint32(len(fakeNameSuffix))]
+}
+
+// isSQLIdentifier determines whether the provided name is safe to interpolate
+// into a SQL statement as a table name.
+func isSQLIdentifier(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math/rand"
+	"regexp"
+	"testing"
+)
+
+func TestGenerateDatasetCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateDataset(500, DatasetCSV, &buf); err != nil {
+		t.Fatalf("generating dataset failed: %s", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("reading generated CSV failed: %s", err)
+	}
+	if len(records) != 501 {
+		t.Fatalf("generated %d rows (expected 501)", len(records))
+	}
+	if records[0][0] != "routing_number" || records[0][1] != "institution_name" {
+		t.Fatalf("generated unexpected header %v", records[0])
+	}
+
+	seen := make(map[string]bool)
+	for _, record := range records[1:] {
+		if err = Validate(record[0]); err != nil {
+			t.Fatalf("generated invalid RTN \"%s\": %s", record[0], err)
+		}
+		if seen[record[0]] {
+			t.Fatalf("generated duplicate RTN \"%s\"", record[0])
+		}
+		seen[record[0]] = true
+
+		if record[1] != fakeInstitutionName(record[0]) {
+			t.Fatalf("generated unexpected name \"%s\" for \"%s\"", record[1], record[0])
+		}
+	}
+}
+
+func TestGenerateDatasetJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateDataset(100, DatasetJSONL, &buf, WithSeed(42)); err != nil {
+		t.Fatalf("generating dataset failed: %s", err)
+	}
+
+	var (
+		scanner = bufio.NewScanner(&buf)
+		record  DatasetRecord
+		lines   int
+	)
+	for scanner.Scan() {
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decoding line \"%s\" failed: %s", scanner.Text(), err)
+		}
+		if err := Validate(record.RoutingNumber); err != nil {
+			t.Fatalf("generated invalid RTN \"%s\": %s", record.RoutingNumber, err)
+		}
+		lines++
+	}
+	if lines != 100 {
+		t.Fatalf("generated %d lines (expected 100)", lines)
+	}
+}
+
+func TestGenerateDatasetSQL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateDataset(50, DatasetSQL, &buf, WithTableName("qa.banks")); err != nil {
+		t.Fatalf("generating dataset failed: %s", err)
+	}
+
+	statement := regexp.MustCompile(
+		`^INSERT INTO qa\.banks \(routing_number, institution_name\) VALUES \('(\d{9})', '[A-Za-z& ]+'\);$`,
+	)
+
+	var (
+		scanner = bufio.NewScanner(&buf)
+		lines   int
+	)
+	for scanner.Scan() {
+		match := statement.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			t.Fatalf("generated unexpected statement \"%s\"", scanner.Text())
+		}
+		if err := Validate(match[1]); err != nil {
+			t.Fatalf("generated invalid RTN \"%s\": %s", match[1], err)
+		}
+		lines++
+	}
+	if lines != 50 {
+		t.Fatalf("generated %d lines (expected 50)", lines)
+	}
+}
+
+func TestGenerateDatasetReproducible(t *testing.T) {
+	for _, format := range []DatasetFormat{DatasetCSV, DatasetJSONL, DatasetSQL} {
+		var a, b, c bytes.Buffer
+		_ = GenerateDataset(200, format, &a, WithSeed(7))
+		_ = GenerateDataset(200, format, &b, WithSeed(7))
+		_ = GenerateDataset(200, format, &c, WithSeed(8))
+
+		if !bytes.Equal(a.Bytes(), b.Bytes()) {
+			t.Fatalf("format %d generated different output from the same seed", format)
+		}
+		if bytes.Equal(a.Bytes(), c.Bytes()) {
+			t.Fatalf("format %d generated identical output from different seeds", format)
+		}
+	}
+}
+
+func TestGenerateDatasetErrors(t *testing.T) {
+	tests := []struct {
+		n        int
+		format   DatasetFormat
+		opts     []GenOption
+		expected error
+	}{
+		{-1, DatasetCSV, nil, ErrInvalidCount},
+		{12e6 + 1, DatasetCSV, nil, ErrInvalidCount},
+		{1, DatasetFormat(99), nil, ErrInvalidDatasetFormat},
+		{1, DatasetSQL, []GenOption{WithTableName("")}, ErrInvalidTableName},
+		{1, DatasetSQL, []GenOption{WithTableName("1banks")}, ErrInvalidTableName},
+		{1, DatasetSQL, []GenOption{WithTableName("banks; DROP TABLE x")}, ErrInvalidTableName},
+		{1, DatasetCSV, []GenOption{WithPrefixRange(20, 13)}, ErrInvalidGenOption},
+		{1e6 + 1, DatasetCSV, []GenOption{WithFedDistrict(4)}, ErrInvalidCount},
+		{0, DatasetSQL, nil, nil},
+	}
+
+	var (
+		buf    bytes.Buffer
+		actual error
+	)
+	for _, test := range tests {
+		buf.Reset()
+		actual = GenerateDataset(test.n, test.format, &buf, test.opts...)
+		if actual != test.expected {
+			t.Fatalf(
+				"input %d, %d generated actual error \"%v\" (expected \"%v\")",
+				test.n,
+				test.format,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []GenOption
+		expected func(prefix int) bool
+	}{
+		{"default", nil, func(p int) bool { return p >= 1 && p <= 12 }},
+		{"electronic", []GenOption{WithPrefixRange(61, 72)}, func(p int) bool { return p >= 61 && p <= 72 }},
+		{"single prefix", []GenOption{WithPrefixRange(80, 80)}, func(p int) bool { return p == 80 }},
+		{"district", []GenOption{WithFedDistrict(12)}, func(p int) bool { return p == 12 }},
+		{
+			"district across ranges",
+			[]GenOption{WithPrefixRange(0, 99), WithFedDistrict(2)},
+			func(p int) bool { return p == 2 || p == 22 || p == 62 },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				var (
+					r    = rand.New(rand.NewSource(1))
+					seen = make(map[int]bool)
+				)
+				for i := 0; i < 5000; i++ {
+					rtn, err := Generate(r, test.opts...)
+					if err != nil {
+						t.Fatalf("generation failed: %s", err)
+					}
+					if err = Validate(rtn); err != nil {
+						t.Fatalf("generated invalid RTN \"%s\": %s", rtn, err)
+					}
+
+					prefix := prefixOf(rtn)
+					if !test.expected(prefix) {
+						t.Fatalf("generated RTN \"%s\" outside of the requested prefixes", rtn)
+					}
+					seen[prefix] = true
+				}
+
+				// Every permitted prefix should appear over enough iterations
+				for p := 0; p <= 99; p++ {
+					if test.expected(p) && !seen[p] {
+						t.Fatalf("prefix %02d was never generated", p)
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	a := rand.New(rand.NewSource(42))
+	b := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 100; i++ {
+		x, _ := Generate(a)
+		y, _ := Generate(b)
+		if x != y {
+			t.Fatalf("identically seeded sources generated \"%s\" and \"%s\"", x, y)
+		}
+	}
+
+	// A nil source falls back to the package-level source
+	rtn, err := Generate(nil)
+	if err != nil || Validate(rtn) != nil {
+		t.Fatalf("nil source generated actual output \"%s\", \"%v\"", rtn, err)
+	}
+}
+
+func TestGenerateInvalidOptions(t *testing.T) {
+	tests := [][]GenOption{
+		{WithPrefixRange(-1, 12)},
+		{WithPrefixRange(1, 100)},
+		{WithPrefixRange(12, 1)},
+		{WithFedDistrict(5), WithPrefixRange(13, 20)},
+		{WithFedDistrict(13)},
+		{WithFedDistrict(-1)},
+		{WithFedDistrict(3), WithPrefixRange(61, 62)},
+		{WithSeed(42)},
+		{WithTableName("banks")},
+		{WithPrefixRange(1, 12), WithSeed(1)},
+	}
+
+	for i, opts := range tests {
+		rtn, err := Generate(nil, opts...)
+		if rtn != "" || err != ErrInvalidGenOption {
+			t.Fatalf("options %d generated actual output \"%s\", \"%v\"", i, rtn, err)
+		}
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/schultz-is/rtnutil
 
-go 1.15
+go 1.20
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyKey indicates that a required key was not provided.
+var ErrEmptyKey = errors.New("empty key")
+
+// HashRTN computes an HMAC-SHA256 over the canonical form of the provided RTN
+// using the provided key. The same RTN hashed with the same key always produces
+// the same result, allowing datasets to be joined or partitioned on RTNs
+// without exchanging the RTNs themselves.
+//
+// Before hashing, input consisting of 7 or 8 digits is left-padded with zeros
+// to 9 digits, since such values have usually lost their leading zeros while
+// passing through numeric fields. The resulting 9 digits are then validated,
+// and the hash is computed over those 9 ASCII digits and nothing else. No other
+// normalization is applied, so "26014601" and "026014601" hash identically but
+// " 026014601" is rejected.
+func HashRTN(rtn string, key []byte) (hash [32]byte, err error) {
+	if len(key) == 0 {
+		return hash, ErrEmptyKey
+	}
+
+	if rtn, err = canonicalizeForHash(rtn); err != nil {
+		return hash, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(rtn))
+	copy(hash[:], mac.Sum(nil))
+
+	return hash, nil
+}
+
+// HashAll computes HashRTN for each of the provided RTNs using the provided
+// key. If any RTN is invalid, an error identifying its index is returned.
+func HashAll(rtns []string, key []byte) (hashes [][32]byte, err error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+
+	var (
+		mac = hmac.New(sha256.New, key)
+		rtn string
+		i   int
+	)
+
+	hashes = make([][32]byte, len(rtns))
+	for i = range rtns {
+		if rtn, err = canonicalizeForHash(rtns[i]); err != nil {
+			return nil, fmt.Errorf("rtn %d: %w", i, err)
+		}
+
+		mac.Reset()
+		_, _ = mac.Write([]byte(rtn))
+		copy(hashes[i][:], mac.Sum(nil))
+	}
+
+	return hashes, nil
+}
+
+// canonicalizeForHash restores leading zeros lost from 7- and 8-digit input and
+// validates the result.
+func canonicalizeForHash(rtn string) (canonical string, err error) {
+	if len(rtn) >= minRepairableLength && len(rtn) < 9 {
+		if indexNonDigit(rtn) >= 0 {
+			return "", ErrInvalidCharacter
+		}
+
+		rtn = padLeadingZeros(rtn, 9)
+	}
+
+	if err = Validate(rtn); err != nil {
+		return "", err
+	}
+
+	return rtn, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestHashRTN(t *testing.T) {
+	key := []byte("test key")
+
+	tests := []struct {
+		input         string
+		canonical     string
+		expectedError error
+	}{
+		{"026014601", "026014601", nil},
+		{"26014601", "026014601", nil},
+		{"091000022", "091000022", nil},
+		{"91000022", "091000022", nil},
+		{"011000015", "011000015", nil},
+		{"11000015", "011000015", nil},
+		{"1000015", "001000015", ErrChecksumMismatch},
+		{" 026014601", "", ErrIncorrectLength},
+		{"2601460R", "", ErrInvalidCharacter},
+		{"123456789", "", ErrChecksumMismatch},
+		{"123456", "", ErrIncorrectLength},
+	}
+
+	for _, test := range tests {
+		actual, err := HashRTN(test.input, key)
+		if !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual error \"%v\" (expected \"%v\")",
+				test.input,
+				err,
+				test.expectedError,
+			)
+		}
+		if err != nil {
+			continue
+		}
+
+		mac := hmac.New(sha256.New, key)
+		_, _ = mac.Write([]byte(test.canonical))
+		if !hmac.Equal(actual[:], mac.Sum(nil)) {
+			t.Fatalf("input \"%s\" was not hashed as \"%s\"", test.input, test.canonical)
+		}
+	}
+}
+
+func TestHashRTNPinned(t *testing.T) {
+	// Changing this value would break joins against previously hashed data
+	const expected = "39bcea848aedf303bcae3924276e5f7fc1a3db856eb457e01e3187c55a8666c6"
+
+	actual, err := HashRTN("026014601", []byte("rtnutil"))
+	if err != nil {
+		t.Fatalf("hashing failed: %s", err)
+	}
+
+	if hex.EncodeToString(actual[:]) != expected {
+		t.Fatalf("generated actual hash \"%x\" (expected \"%s\")", actual, expected)
+	}
+}
+
+func TestHashRTNEmptyKey(t *testing.T) {
+	if _, err := HashRTN("026014601", nil); err != ErrEmptyKey {
+		t.Fatalf("nil key generated actual error \"%v\" (expected \"%v\")", err, ErrEmptyKey)
+	}
+	if _, err := HashAll([]string{"026014601"}, []byte{}); err != ErrEmptyKey {
+		t.Fatalf("empty key generated actual error \"%v\" (expected \"%v\")", err, ErrEmptyKey)
+	}
+}
+
+func TestHashAll(t *testing.T) {
+	key := []byte("test key")
+	rtns := []string{"026014601", "26014601", "322286188"}
+
+	hashes, err := HashAll(rtns, key)
+	if err != nil {
+		t.Fatalf("hashing failed: %s", err)
+	}
+	if len(hashes) != len(rtns) {
+		t.Fatalf("generated %d hashes (expected %d)", len(hashes), len(rtns))
+	}
+
+	for i, rtn := range rtns {
+		expected, _ := HashRTN(rtn, key)
+		if hashes[i] != expected {
+			t.Fatalf("hash %d does not match HashRTN(\"%s\")", i, rtn)
+		}
+	}
+
+	_, err = HashAll([]string{"026014601", "123456789"}, key)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("invalid RTN generated actual error \"%v\"", err)
+	}
+}
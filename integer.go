@@ -0,0 +1,76 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrOutOfRange indicates that a numeric RTN is negative or has more than 9
+// digits.
+var ErrOutOfRange = errors.New("out of range")
+
+// maxRTN is the greatest value with no more than 9 digits.
+const maxRTN = 999999999
+
+// FromInt converts the provided integer into an RTN, restoring any leading
+// zeros lost while it was stored as a number, and validates the result.
+// Negative values and values with more than 9 digits produce ErrOutOfRange. A
+// value which still fails the checksum once padded produces a *ChecksumError,
+// since it has lost more than its leading zeros.
+//
+// Integers can't represent leading zeros, so the canonical form of an RTN is
+// always the 9-character string; integers should only be used to carry RTNs
+// through systems which can't store them as strings.
+func FromInt(n int) (rtn string, err error) {
+	if n < 0 || n > maxRTN {
+		return "", ErrOutOfRange
+	}
+
+	rtn = padLeadingZeros(strconv.Itoa(n), 9)
+	if err = Validate(rtn); err != nil {
+		return "", err
+	}
+
+	return rtn, nil
+}
+
+// ToInt validates the provided RTN as Parse does and converts it into an
+// integer. Any leading zeros are lost in the conversion and are restored by
+// FromInt.
+func ToInt(rtn string) (n int, err error) {
+	if err = Validate(rtn); err != nil {
+		return 0, err
+	}
+
+	// The RTN is made up of 9 digits, so the conversion cannot fail
+	n, _ = strconv.Atoi(rtn)
+
+	return n, nil
+}
+
+// Encode validates the provided RTN as Parse does and converts it into a
+// compact integer, which is useful for storing large numbers of RTNs in memory.
+// Decode reverses the conversion.
+func Encode(rtn string) (v uint32, err error) {
+	n, err := ToInt(rtn)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(n), nil
+}
+
+// Decode converts an integer produced by Encode back into an RTN, restoring its
+// leading zeros and validating it as FromInt does. Values greater than
+// 999999999 produce ErrOutOfRange.
+func Decode(v uint32) (rtn string, err error) {
+	if v > maxRTN {
+		return "", ErrOutOfRange
+	}
+
+	return FromInt(int(v))
+}
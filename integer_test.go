@@ -0,0 +1,164 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestFromInt(t *testing.T) {
+	tests := []struct {
+		input         int
+		expectedRTN   string
+		expectedError error
+	}{
+		{322286188, "322286188", nil},
+		{26014601, "026014601", nil},
+		{1000009, "001000009", nil},
+		{0, "000000000", nil},
+		{123456789, "", ErrChecksumMismatch},
+		{2601460, "", ErrChecksumMismatch},
+		{-26014601, "", ErrOutOfRange},
+		{1000000000, "", ErrOutOfRange},
+	}
+
+	for _, test := range tests {
+		rtn, err := FromInt(test.input)
+		if rtn != test.expectedRTN || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%d\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				rtn,
+				err,
+				test.expectedRTN,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestToInt(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedInt   int
+		expectedError error
+	}{
+		{"322286188", 322286188, nil},
+		{"026014601", 26014601, nil},
+		{"001000009", 1000009, nil},
+		{"000000000", 0, nil},
+		{"123456789", 0, ErrChecksumMismatch},
+		{"26014601", 0, ErrIncorrectLength},
+		{"-26014601", 0, ErrInvalidCharacter},
+	}
+
+	for _, test := range tests {
+		n, err := ToInt(test.input)
+		if n != test.expectedInt || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%d\", \"%v\" (expected \"%d\", \"%v\")",
+				test.input,
+				n,
+				err,
+				test.expectedInt,
+				test.expectedError,
+			)
+		}
+
+		// Valid RTNs must survive a round trip
+		if err == nil {
+			if rtn, err := FromInt(n); rtn != test.input || err != nil {
+				t.Fatalf("input \"%s\" round-tripped to \"%s\", \"%v\"", test.input, rtn, err)
+			}
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue uint32
+		expectedError error
+	}{
+		{"322286188", 322286188, nil},
+		{"026014601", 26014601, nil},
+		{"000000000", 0, nil},
+		{"123456789", 0, ErrChecksumMismatch},
+		{"02601460", 0, ErrIncorrectLength},
+	}
+
+	for _, test := range tests {
+		v, err := Encode(test.input)
+		if v != test.expectedValue || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%d\", \"%v\" (expected \"%d\", \"%v\")",
+				test.input,
+				v,
+				err,
+				test.expectedValue,
+				test.expectedError,
+			)
+		}
+
+		if err == nil {
+			if rtn, err := Decode(v); rtn != test.input || err != nil {
+				t.Fatalf("input \"%s\" decoded to \"%s\", \"%v\"", test.input, rtn, err)
+			}
+		}
+	}
+
+	if _, err := Decode(123456789); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("invalid value generated actual error \"%v\"", err)
+	}
+	if _, err := Decode(1000000000); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("out of range value generated actual error \"%v\"", err)
+	}
+}
+
+// benchmarkRTNs generates a set of distinct valid RTNs.
+func benchmarkRTNs(n int) (rtns []string) {
+	rtns = make([]string, n)
+	for i := range rtns {
+		rtns[i], _ = Complete(padLeadingZeros(strconv.Itoa(i*7919%100000000), 8))
+	}
+
+	return rtns
+}
+
+func BenchmarkLookupUint32(b *testing.B) {
+	var (
+		rtns  = benchmarkRTNs(100000)
+		index = make(map[uint32]struct{}, len(rtns))
+		keys  = make([]uint32, len(rtns))
+	)
+	for i, rtn := range rtns {
+		keys[i], _ = Encode(rtn)
+		index[keys[i]] = struct{}{}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = index[keys[i%len(keys)]]
+	}
+}
+
+func BenchmarkLookupString(b *testing.B) {
+	var (
+		rtns  = benchmarkRTNs(100000)
+		index = make(map[string]struct{}, len(rtns))
+	)
+	for _, rtn := range rtns {
+		index[rtn] = struct{}{}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = index[rtns[i%len(rtns)]]
+	}
+}
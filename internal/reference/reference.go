@@ -0,0 +1,65 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+// Package reference provides a deliberately straightforward implementation of
+// ABA RTN validation. It favors obviousness over performance and shares no code
+// with the rtnutil package, providing an independent derivation of the same
+// arithmetic against which the optimized implementation can be checked.
+package reference
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// Result classifies the outcome of validating an RTN.
+type Result int
+
+const (
+	// Valid indicates that an RTN is valid.
+	Valid Result = iota
+
+	// IncorrectLength indicates that an RTN is not 9 bytes long.
+	IncorrectLength
+
+	// InvalidCharacter indicates that an RTN contains a byte which is not an
+	// ASCII digit.
+	InvalidCharacter
+
+	// ChecksumMismatch indicates that an RTN's check digit does not match its
+	// remaining digits.
+	ChecksumMismatch
+)
+
+// weights are the multipliers applied to each of the 9 digits of an RTN.
+var weights = [9]int64{3, 7, 1, 3, 7, 1, 3, 7, 1}
+
+// Validate classifies the provided RTN.
+func Validate(rtn string) Result {
+	b := []byte(rtn)
+	if len(b) != len(weights) {
+		return IncorrectLength
+	}
+
+	digits := make([]int64, 0, len(b))
+	for _, c := range b {
+		digit, err := strconv.ParseInt(string([]byte{c}), 10, 64)
+		if err != nil || digit < 0 || digit > 9 {
+			return InvalidCharacter
+		}
+
+		digits = append(digits, digit)
+	}
+
+	sum := new(big.Int)
+	for i, digit := range digits {
+		sum.Add(sum, big.NewInt(digit*weights[i]))
+	}
+
+	if new(big.Int).Mod(sum, big.NewInt(10)).Sign() != 0 {
+		return ChecksumMismatch
+	}
+
+	return Valid
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package reference
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Result
+	}{
+		{"asdf", IncorrectLength},
+		{"0123456789", IncorrectLength},
+		{"R00000000", InvalidCharacter},
+		{"+00000000", InvalidCharacter},
+		{"-00000000", InvalidCharacter},
+		{"123456789", ChecksumMismatch},
+		{"000000000", Valid},
+		{"322286188", Valid},
+		{"021200025", Valid},
+		{"026014601", Valid},
+	}
+
+	var actual Result
+	for _, test := range tests {
+		actual = Validate(test.input)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%s\" generated actual result %d (expected %d)",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// LineSummary counts the lines processed by ValidateLines.
+type LineSummary struct {
+	Valid   int
+	Invalid int
+	Blank   int // Blank counts only lines skipped by SkipBlankLines
+}
+
+// lineConfig holds the configuration built from a set of LineOptions.
+type lineConfig struct {
+	skipBlank bool
+	loose     bool
+	scheme    Scheme
+	redaction *RedactionPolicy
+}
+
+// LineOption configures the behavior of ValidateLines.
+type LineOption func(*lineConfig)
+
+// SkipBlankLines causes lines which are empty once trimmed to be counted as
+// blank rather than validated. By default, blank lines are validated like any
+// other line and fail with ErrIncorrectLength.
+func SkipBlankLines() LineOption {
+	return func(c *lineConfig) {
+		c.skipBlank = true
+	}
+}
+
+// LooseLines causes each line to be validated as ValidateLoose does, so that
+// separators such as spaces and hyphens within an RTN are accepted. The RTN
+// passed to the callback is still the trimmed line as read.
+func LooseLines() LineOption {
+	return func(c *lineConfig) {
+		c.loose = true
+	}
+}
+
+// WithLineScheme causes each line to be validated under the provided scheme
+// rather than as an ABA RTN. LooseLines removes separators before the scheme
+// validates each line.
+func WithLineScheme(s Scheme) LineOption {
+	return func(c *lineConfig) {
+		c.scheme = s
+	}
+}
+
+// WithLineRedaction causes the RTN and error passed to the callback for each
+// line to be redacted under the provided policy, as RedactionPolicy.Redact and
+// RedactionPolicy.RedactError do, so that reports built from them don't expose
+// RTNs. Lines are validated before they are redacted.
+func WithLineRedaction(p RedactionPolicy) LineOption {
+	return func(c *lineConfig) {
+		c.redaction = &p
+	}
+}
+
+// ValidateLines reads the provided reader line by line, trims surrounding
+// whitespace from each line, and validates it as Parse does unless LooseLines
+// or WithLineScheme is provided. The provided function is called with the one-
+// based line number, the trimmed RTN, and the result of validation for each
+// line, and may return false to stop reading early. A nil function only tallies
+// the results. Lines are streamed rather than buffered, so memory use does not
+// grow with the size of the input. The returned summary covers the lines read
+// before stopping, and the returned error reports only failures to read; lines
+// longer than bufio.MaxScanTokenSize produce bufio.ErrTooLong.
+func ValidateLines(
+	r io.Reader,
+	fn func(line int, rtn string, err error) bool,
+	opts ...LineOption,
+) (summary LineSummary, err error) {
+	var c lineConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	var (
+		scanner = bufio.NewScanner(r)
+		line    int
+		trimmed []byte
+		rtn     string
+	)
+
+	for scanner.Scan() {
+		line++
+
+		// Trimming whitespace also removes the CR of CRLF line endings
+		trimmed = bytes.TrimSpace(scanner.Bytes())
+		if len(trimmed) == 0 && c.skipBlank {
+			summary.Blank++
+			continue
+		}
+
+		rtn = string(trimmed)
+		err = validateScheme(c.scheme, rtn, c.loose)
+		if err != nil {
+			summary.Invalid++
+		} else {
+			summary.Valid++
+		}
+
+		if c.redaction != nil {
+			rtn, err = c.redaction.Redact(rtn), c.redaction.RedactError("", err)
+		}
+
+		if fn != nil && !fn(line, rtn, err) {
+			return summary, nil
+		}
+	}
+
+	return summary, scanner.Err()
+}
@@ -0,0 +1,278 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestValidateLines(t *testing.T) {
+	type result struct {
+		line int
+		rtn  string
+		err  error
+	}
+
+	tests := []struct {
+		input           string
+		opts            []LineOption
+		expectedResults []result
+		expectedSummary LineSummary
+	}{
+		{
+			"026014601\n322286188\n",
+			nil,
+			[]result{{1, "026014601", nil}, {2, "322286188", nil}},
+			LineSummary{Valid: 2},
+		},
+		{
+			"  026014601 \r\n\t123456789\r\n02601460O",
+			nil,
+			[]result{
+				{1, "026014601", nil},
+				{2, "123456789", ErrChecksumMismatch},
+				{3, "02601460O", ErrInvalidCharacter},
+			},
+			LineSummary{Valid: 1, Invalid: 2},
+		},
+		{
+			"026014601\n\n  \n322286188\n",
+			nil,
+			[]result{
+				{1, "026014601", nil},
+				{2, "", ErrIncorrectLength},
+				{3, "", ErrIncorrectLength},
+				{4, "322286188", nil},
+			},
+			LineSummary{Valid: 2, Invalid: 2},
+		},
+		{
+			"026014601\n\n  \n322286188\n",
+			[]LineOption{SkipBlankLines()},
+			[]result{{1, "026014601", nil}, {4, "322286188", nil}},
+			LineSummary{Valid: 2, Blank: 2},
+		},
+		{
+			"0260-1460-1\n3222 8618 8\n0212-0002-6\n",
+			[]LineOption{LooseLines()},
+			[]result{
+				{1, "0260-1460-1", nil},
+				{2, "3222 8618 8", nil},
+				{3, "0212-0002-6", ErrChecksumMismatch},
+			},
+			LineSummary{Valid: 2, Invalid: 1},
+		},
+		{
+			"12340\n12-34-0\n021200025\n12341\n",
+			[]LineOption{WithLineScheme(sumScheme{})},
+			[]result{
+				{1, "12340", nil},
+				{2, "12-34-0", ErrIncorrectLength},
+				{3, "021200025", ErrIncorrectLength},
+				{4, "12341", ErrChecksumMismatch},
+			},
+			LineSummary{Valid: 1, Invalid: 3},
+		},
+		{
+			"12-34-0\n1234O\n",
+			[]LineOption{WithLineScheme(sumScheme{}), LooseLines()},
+			[]result{{1, "12-34-0", nil}, {2, "1234O", ErrInvalidCharacter}},
+			LineSummary{Valid: 1, Invalid: 1},
+		},
+		{
+			"0260-1460-1\n",
+			nil,
+			[]result{{1, "0260-1460-1", ErrIncorrectLength}},
+			LineSummary{Invalid: 1},
+		},
+		{
+			"",
+			nil,
+			nil,
+			LineSummary{},
+		},
+	}
+
+	for _, test := range tests {
+		var actual []result
+		summary, err := ValidateLines(
+			strings.NewReader(test.input),
+			func(line int, rtn string, err error) bool {
+				actual = append(actual, result{line, rtn, err})
+				return true
+			},
+			test.opts...,
+		)
+		if err != nil || summary != test.expectedSummary {
+			t.Fatalf(
+				"input %q generated actual summary %+v, \"%v\" (expected %+v)",
+				test.input,
+				summary,
+				err,
+				test.expectedSummary,
+			)
+		}
+
+		if len(actual) != len(test.expectedResults) {
+			t.Fatalf(
+				"input %q generated actual results %v (expected %v)",
+				test.input,
+				actual,
+				test.expectedResults,
+			)
+		}
+		for i, expected := range test.expectedResults {
+			if actual[i].line != expected.line ||
+				actual[i].rtn != expected.rtn ||
+				!errors.Is(actual[i].err, expected.err) {
+				t.Fatalf(
+					"input %q generated actual result %v (expected %v)",
+					test.input,
+					actual[i],
+					expected,
+				)
+			}
+		}
+	}
+}
+
+func TestValidateLinesRedaction(t *testing.T) {
+	const input = "322286188\n123456789\n02120O025\n0212\n0212-0002-5\n"
+
+	var actual []string
+	summary, err := ValidateLines(
+		strings.NewReader(input),
+		func(line int, rtn string, err error) bool {
+			actual = append(actual, fmt.Sprintf("%s: %v", rtn, err))
+			return true
+		},
+		LooseLines(),
+		WithLineRedaction(RedactionPolicy{FullRedact: true}),
+	)
+	if err != nil || summary != (LineSummary{Valid: 2, Invalid: 3}) {
+		t.Fatalf("redacting generated actual summary %+v, \"%v\"", summary, err)
+	}
+
+	for _, report := range actual {
+		if strings.IndexFunc(report, unicode.IsDigit) >= 0 {
+			t.Fatalf("redacting generated report containing digits \"%s\"", report)
+		}
+	}
+
+	_, err = ValidateLines(
+		strings.NewReader("123456789\n"),
+		func(line int, rtn string, err error) bool {
+			if rtn != "1234***89" || !errors.Is(err, ErrChecksumMismatch) {
+				t.Fatalf("redacting generated actual result \"%s\", \"%v\"", rtn, err)
+			}
+			return true
+		},
+		WithLineRedaction(DefaultRedactionPolicy),
+	)
+	if err != nil {
+		t.Fatalf("redacting generated unexpected error \"%v\"", err)
+	}
+}
+
+func TestValidateLinesStop(t *testing.T) {
+	var calls int
+	summary, err := ValidateLines(
+		strings.NewReader("026014601\n123456789\n322286188\n"),
+		func(line int, rtn string, err error) bool {
+			calls++
+			return err == nil
+		},
+	)
+	if err != nil || calls != 2 || summary != (LineSummary{Valid: 1, Invalid: 1}) {
+		t.Fatalf("stopping early generated actual summary %+v, %d calls, \"%v\"", summary, calls, err)
+	}
+}
+
+func TestValidateLinesTooLong(t *testing.T) {
+	_, err := ValidateLines(strings.NewReader(strings.Repeat("0", bufio.MaxScanTokenSize+1)), nil)
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("overlong line generated actual error \"%v\"", err)
+	}
+}
+
+// lineGenerator is a reader which lazily produces the provided number of lines,
+// alternating between a valid and an invalid RTN.
+type lineGenerator struct {
+	remaining int
+	pending   []byte
+}
+
+func (g *lineGenerator) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(g.pending) == 0 {
+			if g.remaining == 0 {
+				break
+			}
+			if g.remaining%2 == 0 {
+				g.pending = []byte("026014601\r\n")
+			} else {
+				g.pending = []byte("123456789\r\n")
+			}
+			g.remaining--
+		}
+
+		copied := copy(p[n:], g.pending)
+		g.pending = g.pending[copied:]
+		n += copied
+	}
+
+	if n == 0 {
+		return 0, io.EOF
+	}
+
+	return n, nil
+}
+
+func TestValidateLinesMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large input in short mode")
+	}
+
+	const lines = 2000000 // 22MB of input
+
+	var (
+		stats    runtime.MemStats
+		baseline uint64
+		peak     uint64
+	)
+
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	baseline = stats.HeapAlloc
+
+	summary, err := ValidateLines(
+		&lineGenerator{remaining: lines},
+		func(line int, rtn string, err error) bool {
+			if line%100000 == 0 {
+				runtime.ReadMemStats(&stats)
+				if stats.HeapAlloc > peak {
+					peak = stats.HeapAlloc
+				}
+			}
+			return true
+		},
+	)
+	if err != nil || summary != (LineSummary{Valid: lines / 2, Invalid: lines / 2}) {
+		t.Fatalf("large input generated actual summary %+v, \"%v\"", summary, err)
+	}
+
+	// Garbage may accumulate between collections, but the heap must not grow in
+	// proportion to the input
+	if peak > baseline+8<<20 {
+		t.Fatalf("large input grew the heap from %d to %d bytes", baseline, peak)
+	}
+}
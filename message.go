@@ -0,0 +1,190 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// Stable, language-independent codes identifying the errors returned by this
+// package. These are suitable for use as keys in message catalogs and in
+// machine-readable responses.
+const (
+	CodeIncorrectLength       = "incorrect_length"
+	CodeInvalidCharacter      = "invalid_character"
+	CodeChecksumMismatch      = "checksum_mismatch"
+	CodeTooManyMissingDigits  = "too_many_missing_digits"
+	CodeNoMissingDigits       = "no_missing_digits"
+	CodeInvalidFraction       = "invalid_fraction"
+	CodeUnassignedPrefix      = "unassigned_prefix"
+	CodeMissingTransitField   = "missing_transit_field"
+	CodeMultipleTransitFields = "multiple_transit_fields"
+	CodeInvalidMICRLine       = "invalid_micr_line"
+	CodeOutOfRange            = "out_of_range"
+	CodeInvalidPlaceholder    = "invalid_placeholder"
+	CodeNotRepairable         = "not_repairable"
+	CodeParticipantNotFound   = "participant_not_found"
+	CodeSuccessorNotFound     = "successor_not_found"
+	CodeSuccessorCycle        = "successor_cycle"
+	CodeUnknown               = "unknown"
+)
+
+// DefaultLanguage is the language used when no messages are registered for a
+// requested language or code.
+const DefaultLanguage = "en"
+
+// errorCodes maps each sentinel error to its stable code. Errors which wrap
+// other sentinels are listed before the sentinels they wrap.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrNotRepairable, CodeNotRepairable},
+	{ErrIncorrectLength, CodeIncorrectLength},
+	{ErrInvalidCharacter, CodeInvalidCharacter},
+	{ErrChecksumMismatch, CodeChecksumMismatch},
+	{ErrTooManyMissingDigits, CodeTooManyMissingDigits},
+	{ErrNoMissingDigits, CodeNoMissingDigits},
+	{ErrInvalidFraction, CodeInvalidFraction},
+	{ErrUnassignedPrefix, CodeUnassignedPrefix},
+	{ErrMissingTransitField, CodeMissingTransitField},
+	{ErrMultipleTransitFields, CodeMultipleTransitFields},
+	{ErrInvalidMICRLine, CodeInvalidMICRLine},
+	{ErrOutOfRange, CodeOutOfRange},
+	{ErrInvalidPlaceholder, CodeInvalidPlaceholder},
+	{ErrParticipantNotFound, CodeParticipantNotFound},
+	{ErrSuccessorNotFound, CodeSuccessorNotFound},
+	{ErrSuccessorCycle, CodeSuccessorCycle},
+}
+
+// messages is the catalog of user-presentable messages keyed by language and
+// then by error code.
+var (
+	messagesMu sync.RWMutex
+	messages   = map[string]map[string]string{
+		"en": {
+			CodeIncorrectLength:       "The routing number must be 9 digits long.",
+			CodeInvalidCharacter:      "The routing number contains an invalid character.",
+			CodeChecksumMismatch:      "The routing number is not valid. Please check the digits and try again.",
+			CodeTooManyMissingDigits:  "The routing number is missing more than one digit.",
+			CodeNoMissingDigits:       "The routing number is not missing any digits.",
+			CodeInvalidFraction:       "The routing number fraction is not in a recognized format.",
+			CodeUnassignedPrefix:      "The routing number does not belong to a recognized Federal Reserve range.",
+			CodeMissingTransitField:   "The check line does not contain a routing number.",
+			CodeMultipleTransitFields: "The check line contains more than one routing number.",
+			CodeInvalidMICRLine:       "The check line is not in a recognized format.",
+			CodeOutOfRange:            "The routing number must be a number with at most 9 digits.",
+			CodeInvalidPlaceholder:    "The character marking a missing digit cannot itself be a digit.",
+			CodeNotRepairable:         "The routing number is too short or contains non-digits, so its leading zeros cannot be restored.",
+			CodeParticipantNotFound:   "The routing number is not listed in the directory.",
+			CodeSuccessorNotFound:     "The routing number has been replaced, but its replacement is not listed in the directory.",
+			CodeSuccessorCycle:        "The routing number has been replaced, but its replacements could not be followed.",
+			CodeUnknown:               "The routing number could not be validated.",
+		},
+		"es": {
+			CodeIncorrectLength:       "El número de ruta debe tener 9 dígitos.",
+			CodeInvalidCharacter:      "El número de ruta contiene un carácter no válido.",
+			CodeChecksumMismatch:      "El número de ruta no es válido. Revise los dígitos e inténtelo de nuevo.",
+			CodeTooManyMissingDigits:  "Al número de ruta le falta más de un dígito.",
+			CodeNoMissingDigits:       "Al número de ruta no le falta ningún dígito.",
+			CodeInvalidFraction:       "La fracción del número de ruta no tiene un formato reconocido.",
+			CodeUnassignedPrefix:      "El número de ruta no pertenece a un rango reconocido de la Reserva Federal.",
+			CodeMissingTransitField:   "La línea del cheque no contiene un número de ruta.",
+			CodeMultipleTransitFields: "La línea del cheque contiene más de un número de ruta.",
+			CodeInvalidMICRLine:       "La línea del cheque no tiene un formato reconocido.",
+			CodeOutOfRange:            "El número de ruta debe ser un número de 9 dígitos como máximo.",
+			CodeInvalidPlaceholder:    "El carácter que marca un dígito faltante no puede ser un dígito.",
+			CodeNotRepairable:         "El número de ruta es demasiado corto o contiene caracteres que no son dígitos, por lo que no se pueden restaurar sus ceros iniciales.",
+			CodeParticipantNotFound:   "El número de ruta no figura en el directorio.",
+			CodeSuccessorNotFound:     "El número de ruta ha sido reemplazado, pero su reemplazo no figura en el directorio.",
+			CodeSuccessorCycle:        "El número de ruta ha sido reemplazado, pero no se pudieron seguir sus reemplazos.",
+			CodeUnknown:               "No se pudo validar el número de ruta.",
+		},
+	}
+)
+
+// ErrorCode returns the stable code for the provided error. Errors which do not
+// originate from this package produce CodeUnknown, and a nil error produces an
+// empty string.
+func ErrorCode(err error) (code string) {
+	if err == nil {
+		return ""
+	}
+
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+
+	return CodeUnknown
+}
+
+// Message returns a user-presentable message describing the provided error in
+// the requested language. Languages may be provided as a base language ("es")
+// or with a region ("es-MX"); a message from the base language is used if the
+// region has none registered, followed by a message from DefaultLanguage.
+// Messages never include the input which produced the error. A nil error
+// produces an empty string.
+func Message(err error, lang string) (msg string) {
+	if err == nil {
+		return ""
+	}
+
+	var code = ErrorCode(err)
+
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+
+	for _, l := range languageFallbacks(lang) {
+		if msg = messages[l][code]; msg != "" {
+			return msg
+		}
+	}
+
+	return messages[DefaultLanguage][CodeUnknown]
+}
+
+// RegisterMessages adds the provided messages, keyed by error code, to the
+// catalog for the provided language. Messages for codes already present in the
+// catalog are replaced. It is safe to call RegisterMessages concurrently with
+// Message.
+func RegisterMessages(lang string, msgs map[string]string) {
+	lang = normalizeLanguage(lang)
+
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+
+	catalog, ok := messages[lang]
+	if !ok {
+		catalog = make(map[string]string, len(msgs))
+		messages[lang] = catalog
+	}
+
+	for code, msg := range msgs {
+		catalog[code] = msg
+	}
+}
+
+// languageFallbacks returns the ordered set of catalog languages to consult for
+// the provided language.
+func languageFallbacks(lang string) (langs []string) {
+	lang = normalizeLanguage(lang)
+	langs = append(langs, lang)
+
+	if i := strings.IndexByte(lang, '-'); i > 0 {
+		langs = append(langs, lang[:i])
+	}
+
+	return append(langs, DefaultLanguage)
+}
+
+// normalizeLanguage converts a language tag into the form used as a catalog
+// key.
+func normalizeLanguage(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
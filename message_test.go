@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		input    error
+		expected string
+	}{
+		{nil, ""},
+		{ErrIncorrectLength, CodeIncorrectLength},
+		{ErrInvalidCharacter, CodeInvalidCharacter},
+		{ErrChecksumMismatch, CodeChecksumMismatch},
+		{ErrTooManyMissingDigits, CodeTooManyMissingDigits},
+		{ErrNoMissingDigits, CodeNoMissingDigits},
+		{ErrInvalidFraction, CodeInvalidFraction},
+		{ErrUnassignedPrefix, CodeUnassignedPrefix},
+		{ErrMissingTransitField, CodeMissingTransitField},
+		{ErrMultipleTransitFields, CodeMultipleTransitFields},
+		{ErrInvalidMICRLine, CodeInvalidMICRLine},
+		{ErrOutOfRange, CodeOutOfRange},
+		{ErrInvalidPlaceholder, CodeInvalidPlaceholder},
+		{ErrNotRepairable, CodeNotRepairable},
+		{fmt.Errorf("%w: %w", ErrNotRepairable, ErrIncorrectLength), CodeNotRepairable},
+		{fmt.Errorf("%w: %w", ErrNotRepairable, &InvalidCharacterError{Index: 2, Rune: 'O'}), CodeNotRepairable},
+		{ErrParticipantNotFound, CodeParticipantNotFound},
+		{ErrSuccessorNotFound, CodeSuccessorNotFound},
+		{ErrSuccessorCycle, CodeSuccessorCycle},
+		{fmt.Errorf("wrapped: %w", ErrChecksumMismatch), CodeChecksumMismatch},
+		{errors.New("something else"), CodeUnknown},
+	}
+
+	var actual string
+	for _, test := range tests {
+		actual = ErrorCode(test.input)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%v\" generated actual code \"%s\" (expected \"%s\")",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		err      error
+		lang     string
+		expected string
+	}{
+		{nil, "en", ""},
+		{ErrIncorrectLength, "en", messages["en"][CodeIncorrectLength]},
+		{ErrIncorrectLength, "es", messages["es"][CodeIncorrectLength]},
+		{ErrIncorrectLength, "es-MX", messages["es"][CodeIncorrectLength]},
+		{ErrIncorrectLength, "ES_mx", messages["es"][CodeIncorrectLength]},
+		{ErrChecksumMismatch, "fr", messages["en"][CodeChecksumMismatch]},
+		{ErrChecksumMismatch, "", messages["en"][CodeChecksumMismatch]},
+		{errors.New("something else"), "es", messages["es"][CodeUnknown]},
+	}
+
+	var actual string
+	for _, test := range tests {
+		actual = Message(test.err, test.lang)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%v\" (%s) generated actual message \"%s\" (expected \"%s\")",
+				test.err,
+				test.lang,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestMessageCatalogComplete(t *testing.T) {
+	codes := []string{CodeUnknown}
+	for _, ec := range errorCodes {
+		codes = append(codes, ec.code)
+	}
+
+	for lang, catalog := range messages {
+		for _, code := range codes {
+			if catalog[code] == "" {
+				t.Fatalf("language \"%s\" is missing a message for \"%s\"", lang, code)
+			}
+		}
+	}
+}
+
+func TestMessageOmitsInput(t *testing.T) {
+	inputs := []string{"12345", "R00000000", "123456789"}
+
+	var msg string
+	for _, input := range inputs {
+		for _, lang := range []string{"en", "es"} {
+			msg = Message(Validate(input), lang)
+			if strings.Contains(msg, input) {
+				t.Fatalf("message \"%s\" includes input \"%s\"", msg, input)
+			}
+		}
+	}
+}
+
+func TestRegisterMessages(t *testing.T) {
+	RegisterMessages("pt-BR", map[string]string{
+		CodeChecksumMismatch: "O número de roteamento não é válido.",
+	})
+	defer func() {
+		messagesMu.Lock()
+		delete(messages, "pt-br")
+		messagesMu.Unlock()
+	}()
+
+	tests := []struct {
+		err      error
+		lang     string
+		expected string
+	}{
+		{ErrChecksumMismatch, "pt-BR", "O número de roteamento não é válido."},
+		{ErrIncorrectLength, "pt-BR", messages["en"][CodeIncorrectLength]},
+		{ErrChecksumMismatch, "pt", messages["en"][CodeChecksumMismatch]},
+	}
+
+	var actual string
+	for _, test := range tests {
+		actual = Message(test.err, test.lang)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%v\" (%s) generated actual message \"%s\" (expected \"%s\")",
+				test.err,
+				test.lang,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrMissingTransitField indicates that a MICR line does not contain a routing
+// number enclosed in a pair of transit symbols.
+var ErrMissingTransitField = errors.New("missing transit field")
+
+// ErrMultipleTransitFields indicates that a MICR line contains more than one
+// pair of transit symbols.
+var ErrMultipleTransitFields = errors.New("multiple transit fields")
+
+// ErrInvalidMICRLine indicates that a MICR line contains an unrecognized
+// character or its fields are not arranged as expected.
+var ErrInvalidMICRLine = errors.New("invalid micr line")
+
+// Canonical forms of the E-13B symbols used while parsing MICR lines.
+const (
+	micrTransit = 'T'
+	micrOnUs    = 'O'
+	micrAmount  = 'A'
+	micrDash    = '-'
+)
+
+// MICRLine holds the fields of the MICR line printed along the bottom of a
+// check.
+type MICRLine struct {
+	RTN RTN
+
+	// Account is the account number from the on-us field, including any
+	// dashes
+	Account string
+
+	// CheckNumber is the serial number of the check, taken from the auxiliary
+	// on-us field if present and otherwise from the end of the on-us field
+	CheckNumber string
+
+	// TransactionCode is the end of the on-us field when the check number is
+	// taken from the auxiliary on-us field
+	TransactionCode string
+
+	// Amount is the encoded amount in cents, or an empty string if the amount
+	// field is not present
+	Amount string
+}
+
+// ParseMICRLine splits a MICR line, such as "⑆021200025⑆ 123456789⑈ 1001", into
+// its fields. The E-13B transit (⑆), amount (⑇), on-us (⑈), and dash (⑉)
+// symbols are recognized, as are the ASCII stand-ins emitted by many scanners:
+// 'T' or ':' for transit, 'O' or ';' for on-us, and '-' for dash. Spaces are
+// ignored.
+//
+// A line without a pair of transit symbols produces ErrMissingTransitField,
+// and one with more than a pair produces ErrMultipleTransitFields. The routing
+// number between the transit symbols is validated as Parse does, producing the
+// same errors, with character indexes relative to the routing number. Any
+// other malformation produces ErrInvalidMICRLine.
+func ParseMICRLine(s string) (line MICRLine, err error) {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	// Reduce the line to digits and canonical symbols
+	for _, r := range s {
+		switch r {
+		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			b.WriteRune(r)
+		case '⑆', 'T', ':':
+			b.WriteByte(micrTransit)
+		case '⑈', 'O', ';':
+			b.WriteByte(micrOnUs)
+		case '⑇':
+			b.WriteByte(micrAmount)
+		case '⑉', '-':
+			b.WriteByte(micrDash)
+		case ' ':
+		default:
+			return MICRLine{}, ErrInvalidMICRLine
+		}
+	}
+
+	var fields = strings.Split(b.String(), string(micrTransit))
+	switch {
+	case len(fields) < 3:
+		return MICRLine{}, ErrMissingTransitField
+	case len(fields) > 3:
+		return MICRLine{}, ErrMultipleTransitFields
+	}
+
+	if line.RTN, err = Parse(fields[1]); err != nil {
+		return MICRLine{}, err
+	}
+
+	// The auxiliary on-us field, if present, precedes the transit field and is
+	// enclosed in on-us symbols
+	if aux := fields[0]; aux != "" {
+		if len(aux) < 3 || aux[0] != micrOnUs || aux[len(aux)-1] != micrOnUs {
+			return MICRLine{}, ErrInvalidMICRLine
+		}
+		if line.CheckNumber = aux[1 : len(aux)-1]; !isMICRField(line.CheckNumber) {
+			return MICRLine{}, ErrInvalidMICRLine
+		}
+	}
+
+	// The amount field, if present, follows the on-us field and is enclosed in
+	// amount symbols
+	var onUs = fields[2]
+	if i := strings.IndexByte(onUs, micrAmount); i >= 0 {
+		amount := onUs[i:]
+		if len(amount) < 3 || amount[len(amount)-1] != micrAmount {
+			return MICRLine{}, ErrInvalidMICRLine
+		}
+		if line.Amount = amount[1 : len(amount)-1]; !isDigits(line.Amount, 1, len(line.Amount)) {
+			return MICRLine{}, ErrInvalidMICRLine
+		}
+
+		onUs = onUs[:i]
+	}
+
+	// The on-us field holds the account number terminated by an on-us symbol,
+	// optionally followed by the check number or a transaction code
+	var parts []string
+	for _, part := range strings.Split(onUs, string(micrOnUs)) {
+		if part == "" {
+			continue
+		}
+		if !isMICRField(part) {
+			return MICRLine{}, ErrInvalidMICRLine
+		}
+		parts = append(parts, part)
+	}
+
+	switch len(parts) {
+	case 2:
+		if line.CheckNumber != "" {
+			line.TransactionCode = parts[1]
+		} else {
+			line.CheckNumber = parts[1]
+		}
+		fallthrough
+	case 1:
+		line.Account = parts[0]
+	default:
+		return MICRLine{}, ErrInvalidMICRLine
+	}
+
+	return line, nil
+}
+
+// isMICRField determines whether the provided field is made up of digits and
+// dashes, beginning with a digit.
+func isMICRField(field string) bool {
+	if field == "" || field[0] == micrDash {
+		return false
+	}
+
+	for i := 0; i < len(field); i++ {
+		if (field[i] < '0' || field[i] > '9') && field[i] != micrDash {
+			return false
+		}
+	}
+
+	return true
+}
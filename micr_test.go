@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMICRLine(t *testing.T) {
+	type fields struct {
+		rtn, account, checkNumber, transactionCode, amount string
+	}
+
+	tests := []struct {
+		input          string
+		expectedFields fields
+		expectedError  error
+	}{
+		// Personal checks
+		{"⑆021200025⑆ 123456789⑈ 1001", fields{"021200025", "123456789", "1001", "", ""}, nil},
+		{"T021200025T 123456789O 1001", fields{"021200025", "123456789", "1001", "", ""}, nil},
+		{":021200025: 123456789; 1001", fields{"021200025", "123456789", "1001", "", ""}, nil},
+		{"⑆021200025⑆123⑉456⑉789⑈", fields{"021200025", "123-456-789", "", "", ""}, nil},
+		{"⑆021200025⑆ 123456789⑈ 1001 ⑇0000012345⑇", fields{"021200025", "123456789", "1001", "", "0000012345"}, nil},
+		{"⑆026014601⑆ 123456789", fields{"026014601", "123456789", "", "", ""}, nil},
+
+		// Business checks
+		{"⑈001001⑈ ⑆021200025⑆ 123456789⑈", fields{"021200025", "123456789", "001001", "", ""}, nil},
+		{"⑈001001⑈ ⑆021200025⑆ 123456789⑈ 25", fields{"021200025", "123456789", "001001", "25", ""}, nil},
+
+		// Transit fields
+		{"021200025 123456789⑈ 1001", fields{}, ErrMissingTransitField},
+		{"⑆021200025 123456789⑈ 1001", fields{}, ErrMissingTransitField},
+		{"⑆021200025⑆ ⑆026014601⑆ 123456789⑈", fields{}, ErrMultipleTransitFields},
+		{"⑆021200025⑆ 123⑆456789⑈", fields{}, ErrMultipleTransitFields},
+		{"⑆021200026⑆ 123456789⑈ 1001", fields{}, ErrChecksumMismatch},
+		{"⑆02120002⑆ 123456789⑈ 1001", fields{}, ErrIncorrectLength},
+		{"⑆0212⑉0025⑆ 123456789⑈ 1001", fields{}, ErrInvalidCharacter},
+
+		// Other malformations
+		{"⑆021200025⑆ 123456789⑈ 1001?", fields{}, ErrInvalidMICRLine},
+		{"⑆021200025⑆", fields{}, ErrInvalidMICRLine},
+		{"⑆021200025⑆ 123⑈456⑈789⑈", fields{}, ErrInvalidMICRLine},
+		{"⑆021200025⑆ ⑉123456789⑈", fields{}, ErrInvalidMICRLine},
+		{"⑆021200025⑆ 123456789⑈ ⑇0000012345", fields{}, ErrInvalidMICRLine},
+		{"⑆021200025⑆ 123456789⑈ ⑇⑇", fields{}, ErrInvalidMICRLine},
+		{"⑆021200025⑆ 123456789⑈ ⑇00⑉12345⑇", fields{}, ErrInvalidMICRLine},
+		{"001001 ⑆021200025⑆ 123456789⑈", fields{}, ErrInvalidMICRLine},
+		{"⑈⑈ ⑆021200025⑆ 123456789⑈", fields{}, ErrInvalidMICRLine},
+	}
+
+	for _, test := range tests {
+		line, err := ParseMICRLine(test.input)
+		actual := fields{line.RTN.String(), line.Account, line.CheckNumber, line.TransactionCode, line.Amount}
+		if actual != test.expectedFields || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %+v, \"%v\" (expected %+v, \"%v\")",
+				test.input,
+				actual,
+				err,
+				test.expectedFields,
+				test.expectedError,
+			)
+		}
+	}
+}
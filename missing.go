@@ -0,0 +1,184 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrInvalidPlaceholder indicates that a rune provided to mark missing digits
+// is a digit or is not a valid rune.
+var ErrInvalidPlaceholder = errors.New("invalid placeholder")
+
+// MaxMissingDigits is the greatest number of missing digits GetMissingDigits
+// will solve for.
+const MaxMissingDigits = 3
+
+// GetMissingDigits calculates every completion of the provided RTN which
+// satisfies the checksum. Input must be an RTN in MICR format with between one
+// and MaxMissingDigits digits replaced by the character 'X'. Since the final
+// missing digit is always determined by the others, n missing digits produce
+// 10^(n-1) candidates, which are returned in lexicographic order. Invalid
+// characters are reported with an *InvalidCharacterError.
+func GetMissingDigits(rtn string) (candidates []string, err error) {
+	if len(rtn) != 9 {
+		return nil, ErrIncorrectLength
+	}
+
+	var (
+		i         int
+		digitRune rune
+		digit     int
+		ok        bool
+		checksum  int
+		missing   []int
+	)
+
+	// Iterate over each character in the string
+	for i, digitRune = range rtn {
+		// Record the position of each "missing digit" rune
+		if digitRune == 'X' {
+			if len(missing) == MaxMissingDigits {
+				return nil, ErrTooManyMissingDigits
+			}
+
+			missing = append(missing, i)
+			continue
+		}
+
+		// Attempt to convert the character to a digit
+		digit, ok = runeToDigit(digitRune)
+		if !ok {
+			return nil, &InvalidCharacterError{Index: i, Rune: digitRune}
+		}
+
+		// Multiply the digit by its respective multiplier and add to the checksum
+		checksum += digit * checksumMultipliers[i%3]
+	}
+
+	if len(missing) == 0 {
+		return nil, ErrNoMissingDigits
+	}
+
+	var (
+		candidate = []byte(rtn)
+		last      = missing[len(missing)-1]
+		count     = 1
+		combo     int
+		sum       int
+		j         int
+	)
+
+	for j = 1; j < len(missing); j++ {
+		count *= 10
+	}
+	candidates = make([]string, 0, count)
+
+	// Enumerate every combination of the leading missing digits in ascending
+	// order, solving for the final missing digit in each
+	for combo = 0; combo < count; combo++ {
+		sum = checksum
+
+		// Assign the leading missing digits from the most significant digit of
+		// the combination down
+		digit = combo
+		for j = len(missing) - 2; j >= 0; j-- {
+			candidate[missing[j]] = byte('0' + digit%10)
+			sum += (digit % 10) * checksumMultipliers[missing[j]%3]
+			digit /= 10
+		}
+
+		// Exactly one digit satisfies the checksum at the final position
+		for digit = 0; digit < 10; digit++ {
+			if (sum+digit*checksumMultipliers[last%3])%10 == 0 {
+				candidate[last] = byte('0' + digit)
+				break
+			}
+		}
+
+		candidates = append(candidates, string(candidate))
+	}
+
+	return candidates, nil
+}
+
+// GetAssignableMissingDigits calculates the completions of the provided RTN as
+// GetMissingDigits does, excluding those whose first two digits are not
+// assigned to any class of institution, as ValidateStrict rejects. A missing
+// leading digit often produces fewer candidates as a result. If every
+// completion is excluded, an empty slice is returned.
+func GetAssignableMissingDigits(rtn string) (candidates []string, err error) {
+	all, err := GetMissingDigits(rtn)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates = all[:0]
+	for _, candidate := range all {
+		if classifyPrefix(prefixOf(candidate)) != ClassReserved {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates, nil
+}
+
+// GetMissingDigitRune calculates a single unknown digit within the provided RTN
+// as GetMissingDigit does, with the missing digit marked by the provided
+// placeholder rune rather than 'X'. The placeholders 'X' and 'x' are
+// interchangeable. Since placeholders may be multi-byte, input must be 9 runes
+// rather than 9 bytes long. Placeholders which are digits produce
+// ErrInvalidPlaceholder, and invalid characters are reported with an
+// *InvalidCharacterError.
+func GetMissingDigitRune(rtn string, placeholder rune) (digit int, err error) {
+	if _, isDigit := runeToDigit(placeholder); isDigit || placeholder == utf8.RuneError || !utf8.ValidRune(placeholder) {
+		return 0, ErrInvalidPlaceholder
+	}
+
+	if utf8.RuneCountInString(rtn) != 9 {
+		return 0, ErrIncorrectLength
+	}
+
+	var (
+		canonical [9]byte
+		n         int
+		r         rune
+	)
+
+	// Translate the input into the form accepted by GetMissingDigit, so that
+	// errors are reported in the same order
+	for _, r = range rtn {
+		switch _, isDigit := runeToDigit(r); {
+		case isPlaceholder(r, placeholder):
+			canonical[n] = 'X'
+		case isDigit:
+			canonical[n] = byte(r)
+		default:
+			canonical[n] = '?'
+		}
+		n++
+	}
+
+	digit, err = GetMissingDigit(string(canonical[:]))
+	if errors.Is(err, ErrInvalidCharacter) {
+		for i, r := range rtn {
+			if _, isDigit := runeToDigit(r); !isDigit && !isPlaceholder(r, placeholder) {
+				return 0, &InvalidCharacterError{Index: i, Rune: r}
+			}
+		}
+	}
+
+	return digit, err
+}
+
+// isPlaceholder determines whether the provided rune marks a missing digit.
+func isPlaceholder(r, placeholder rune) bool {
+	if placeholder == 'X' || placeholder == 'x' {
+		return r == 'X' || r == 'x'
+	}
+
+	return r == placeholder
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGetMissingDigitsErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected error
+	}{
+		{"asdf", ErrIncorrectLength},
+		{"0123456789", ErrIncorrectLength},
+		{"XXXX86188", ErrTooManyMissingDigits},
+		{"X2X2X6X88", ErrTooManyMissingDigits},
+		{"R2228618X", ErrInvalidCharacter},
+		{"x22286188", ErrInvalidCharacter},
+		{"322286188", ErrNoMissingDigits},
+	}
+
+	for _, test := range tests {
+		candidates, err := GetMissingDigits(test.input)
+		if candidates != nil || !errors.Is(err, test.expected) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %v, \"%v\" (expected \"%v\")",
+				test.input,
+				candidates,
+				err,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestGetMissingDigitsSingle(t *testing.T) {
+	// A single missing digit must agree with GetMissingDigit
+	inputs := []string{
+		"X22286188", "3X2286188", "32X286188", "322X86188", "3222X6188",
+		"32228X188", "322286X88", "3222861X8", "32228618X", "03110064X",
+	}
+
+	for _, input := range inputs {
+		candidates, err := GetMissingDigits(input)
+		if err != nil {
+			t.Fatalf("input \"%s\" generated actual error \"%s\"", input, err)
+		}
+
+		digit, _ := GetMissingDigit(input)
+		expected := strings.Replace(input, "X", strconv.Itoa(digit), 1)
+		if len(candidates) != 1 || candidates[0] != expected {
+			t.Fatalf(
+				"input \"%s\" generated actual candidates %v (expected [%s])",
+				input,
+				candidates,
+				expected,
+			)
+		}
+	}
+}
+
+func TestGetMissingDigitsMultiple(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedCount int
+	}{
+		{"XX2286188", 10},
+		{"3222861XX", 10},
+		{"X2228618X", 10},
+		{"32X28X1X8", 100},
+		{"XXX286188", 100},
+	}
+
+	for _, test := range tests {
+		candidates, err := GetMissingDigits(test.input)
+		if err != nil {
+			t.Fatalf("input \"%s\" generated actual error \"%s\"", test.input, err)
+		}
+		if len(candidates) != test.expectedCount {
+			t.Fatalf(
+				"input \"%s\" generated %d candidates (expected %d)",
+				test.input,
+				len(candidates),
+				test.expectedCount,
+			)
+		}
+		if !sort.StringsAreSorted(candidates) {
+			t.Fatalf("input \"%s\" generated unsorted candidates", test.input)
+		}
+
+		seen := make(map[string]bool)
+		for _, candidate := range candidates {
+			if seen[candidate] {
+				t.Fatalf("input \"%s\" generated duplicate candidate \"%s\"", test.input, candidate)
+			}
+			seen[candidate] = true
+
+			if err = Validate(candidate); err != nil {
+				t.Fatalf("input \"%s\" generated invalid candidate \"%s\"", test.input, candidate)
+			}
+
+			for i := range test.input {
+				if test.input[i] != 'X' && test.input[i] != candidate[i] {
+					t.Fatalf("input \"%s\" generated mismatched candidate \"%s\"", test.input, candidate)
+				}
+			}
+		}
+	}
+
+	// The original RTN must be among the candidates
+	candidates, _ := GetMissingDigits("32X28X1X8")
+	if i := sort.SearchStrings(candidates, "322286188"); i == len(candidates) || candidates[i] != "322286188" {
+		t.Fatalf("candidates do not include the original RTN")
+	}
+}
+
+func TestGetAssignableMissingDigits(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedCount int
+		expectedError error
+	}{
+		{"0212000X", 0, ErrIncorrectLength},
+		{"021200025", 0, ErrNoMissingDigits},
+		{"XXXX00025", 0, ErrTooManyMissingDigits},
+		{"R212000X5", 0, ErrInvalidCharacter},
+
+		// Only the prefix 32 is assignable among 30-39
+		{"3X2286188", 1, nil},
+
+		// Half of the completions have reserved prefixes
+		{"XX2286188", 5, nil},
+
+		// No missing digit falls within the prefix
+		{"3222861XX", 10, nil},
+
+		// The only completion has the reserved prefix 13
+		{"1X0000006", 0, nil},
+	}
+
+	for _, test := range tests {
+		candidates, err := GetAssignableMissingDigits(test.input)
+		if len(candidates) != test.expectedCount || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %q, \"%v\" (expected %d candidates, \"%v\")",
+				test.input,
+				candidates,
+				err,
+				test.expectedCount,
+				test.expectedError,
+			)
+		}
+
+		for _, candidate := range candidates {
+			if err = ValidateStrict(candidate); err != nil {
+				t.Fatalf("input \"%s\" generated unassignable candidate \"%s\"", test.input, candidate)
+			}
+		}
+	}
+}
+
+func TestGetMissingDigitRune(t *testing.T) {
+	tests := []struct {
+		input         string
+		placeholder   rune
+		expectedDigit int
+		expectedError error
+	}{
+		{"3222861?8", '?', 8, nil},
+		{"3222861*8", '*', 8, nil},
+		{"3222861x8", 'X', 8, nil},
+		{"3222861X8", 'x', 8, nil},
+		{"3222861�8", '�', 0, ErrInvalidPlaceholder},
+		{"X22286188", '_', 0, ErrInvalidCharacter},
+		{"3222861…8", '…', 8, nil},
+		{"?2228618?", '?', 0, ErrTooManyMissingDigits},
+		{"322286188", '?', 0, ErrNoMissingDigits},
+		{"32228618", '?', 0, ErrIncorrectLength},
+		{"3222861?8", '8', 0, ErrInvalidPlaceholder},
+		{"3222861?8", '0', 0, ErrInvalidPlaceholder},
+		{"3222861?8", -1, 0, ErrInvalidPlaceholder},
+	}
+
+	for _, test := range tests {
+		digit, err := GetMissingDigitRune(test.input, test.placeholder)
+		if digit != test.expectedDigit || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\", '%c' generated actual output \"%d\", \"%v\" (expected \"%d\", \"%v\")",
+				test.input,
+				test.placeholder,
+				digit,
+				err,
+				test.expectedDigit,
+				test.expectedError,
+			)
+		}
+	}
+
+	// Invalid characters are reported at their byte offset
+	var charErr *InvalidCharacterError
+	_, err := GetMissingDigitRune("…2228R18…", '…')
+	if !errors.As(err, &charErr) || charErr.Index != 7 || charErr.Rune != 'R' {
+		t.Fatalf("invalid character generated actual error \"%v\"", err)
+	}
+}
+
+func TestGetMissingDigitRuneMatchesGetMissingDigit(t *testing.T) {
+	inputs := []string{
+		"asdf", "1234", "0123456789", "XX2286188", "R22286188", "RX2286188",
+		"XR2286188", "322286188", "X22286188", "3X2286188", "32X286188",
+		"322X86188", "3222X6188", "32228X188", "322286X88", "3222861X8",
+		"32228618X", "03110064X",
+	}
+
+	for _, placeholder := range []rune{'X', '?', '*', '#'} {
+		for _, input := range inputs {
+			expectedDigit, expectedError := GetMissingDigit(input)
+
+			input = strings.ReplaceAll(input, "X", string(placeholder))
+			digit, err := GetMissingDigitRune(input, placeholder)
+			if digit != expectedDigit || !reflect.DeepEqual(err, expectedError) {
+				t.Fatalf(
+					"input \"%s\", '%c' generated actual output \"%d\", \"%v\" (expected \"%d\", \"%v\")",
+					input,
+					placeholder,
+					digit,
+					err,
+					expectedDigit,
+					expectedError,
+				)
+			}
+		}
+	}
+}
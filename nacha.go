@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// ValidateEntryRDFI determines whether the receiving DFI identification and
+// check digit fields of a NACHA entry detail record form a valid RTN, as
+// ValidateSplit does.
+func ValidateEntryRDFI(rdfi8, checkDigit string) (err error) {
+	return ValidateSplit(rdfi8, checkDigit)
+}
+
+// SplitRDFI validates the provided RTN and splits it into the 8-digit
+// receiving DFI identification and the check digit, as they appear in
+// separate fields of a NACHA entry detail record. It behaves as SplitRTN does,
+// with the check digit returned as a string.
+func SplitRDFI(rtn string) (rdfi8, check string, err error) {
+	rdfi8, checkDigit, err := SplitRTN(rtn)
+	if err != nil {
+		return "", "", err
+	}
+
+	return rdfi8, strconv.Itoa(checkDigit), nil
+}
+
+// ValidateSplit determines whether the provided 8-digit prefix and check digit,
+// such as the receiving DFI identification and check digit fields of a NACHA
+// entry detail record, form a valid RTN. Invalid characters are reported with
+// an *InvalidCharacterError whose index is relative to the RTN the fields form,
+// so an invalid check digit is reported at index 8. A check digit which doesn't match the prefix is reported with a
+// *CheckDigitError holding the expected digit. Both match the corresponding
+// sentinel errors.
+func ValidateSplit(prefix, checkDigit string) (err error) {
+	if len(prefix) != 8 || len(checkDigit) != 1 {
+		return ErrIncorrectLength
+	}
+
+	if i := indexNonDigit(prefix); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(prefix[i:])
+		return &InvalidCharacterError{Index: i, Rune: r}
+	}
+	if !isDigitByte(checkDigit[0]) {
+		return &InvalidCharacterError{Index: 8, Rune: rune(checkDigit[0])}
+	}
+
+	// The prefix is made up of 8 digits, so computing its check digit cannot
+	// fail
+	expected, _ := ComputeCheckDigit(prefix)
+	if actual := int(checkDigit[0] - '0'); actual != expected {
+		return &CheckDigitError{Expected: expected, Actual: actual}
+	}
+
+	return nil
+}
+
+// SplitRTN validates the provided RTN as Parse does and splits it into its
+// 8-digit prefix and its check digit, such as for populating the separate
+// fields of a NACHA entry detail record.
+func SplitRTN(rtn string) (prefix string, checkDigit int, err error) {
+	if err = Validate(rtn); err != nil {
+		return "", 0, err
+	}
+
+	return rtn[:8], int(rtn[8] - '0'), nil
+}
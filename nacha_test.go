@@ -0,0 +1,170 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateEntryRDFI(t *testing.T) {
+	tests := []struct {
+		rdfi8      string
+		checkDigit string
+		expected   error
+	}{
+		{"1234", "5", ErrIncorrectLength},
+		{"322286188", "8", ErrIncorrectLength},
+		{"32228618", "", ErrIncorrectLength},
+		{"32228618", "88", ErrIncorrectLength},
+		{"3222861R", "8", ErrInvalidCharacter},
+		{"32228618", "R", ErrInvalidCharacter},
+		{"32228618", "7", ErrChecksumMismatch},
+		{"22228618", "8", ErrChecksumMismatch},
+		{"32228618", "8", nil},
+		{"02120002", "5", nil},
+		{"00000000", "0", nil},
+	}
+
+	var actual error
+	for _, test := range tests {
+		actual = ValidateEntryRDFI(test.rdfi8, test.checkDigit)
+		if !errors.Is(actual, test.expected) {
+			t.Fatalf(
+				"input \"%s\", \"%s\" generated actual error \"%v\" (expected \"%v\")",
+				test.rdfi8,
+				test.checkDigit,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestSplitRDFI(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedRDFI8 string
+		expectedCheck string
+		expectedError error
+	}{
+		{"1234", "", "", ErrIncorrectLength},
+		{"R22286188", "", "", ErrInvalidCharacter},
+		{"123456789", "", "", ErrChecksumMismatch},
+		{"322286188", "32228618", "8", nil},
+		{"021200025", "02120002", "5", nil},
+	}
+
+	for _, test := range tests {
+		rdfi8, check, err := SplitRDFI(test.input)
+		if rdfi8 != test.expectedRDFI8 || check != test.expectedCheck || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%s\", \"%v\" (expected \"%s\", \"%s\", \"%v\")",
+				test.input,
+				rdfi8,
+				check,
+				err,
+				test.expectedRDFI8,
+				test.expectedCheck,
+				test.expectedError,
+			)
+		}
+
+		// Split fields must always validate as entry fields
+		if err == nil {
+			if err = ValidateEntryRDFI(rdfi8, check); err != nil {
+				t.Fatalf("split of \"%s\" failed entry validation: %s", test.input, err)
+			}
+		}
+	}
+}
+
+func TestValidateSplit(t *testing.T) {
+	tests := []struct {
+		prefix     string
+		checkDigit string
+		expected   error
+	}{
+		{"1234", "5", ErrIncorrectLength},
+		{"322286188", "8", ErrIncorrectLength},
+		{"32228618", "", ErrIncorrectLength},
+		{"32228618", "88", ErrIncorrectLength},
+		{"3222861R", "8", ErrInvalidCharacter},
+		{"32228618", "R", ErrInvalidCharacter},
+		{"32228618", "7", ErrChecksumMismatch},
+		{"22228618", "8", ErrChecksumMismatch},
+		{"32228618", "8", nil},
+		{"02120002", "5", nil},
+		{"00000000", "0", nil},
+	}
+
+	var actual error
+	for _, test := range tests {
+		actual = ValidateSplit(test.prefix, test.checkDigit)
+		if !errors.Is(actual, test.expected) {
+			t.Fatalf(
+				"input \"%s\", \"%s\" generated actual error \"%v\" (expected \"%v\")",
+				test.prefix,
+				test.checkDigit,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestValidateSplitDetails(t *testing.T) {
+	var (
+		cdErr *CheckDigitError
+		icErr *InvalidCharacterError
+	)
+
+	err := ValidateSplit("32228618", "7")
+	if !errors.As(err, &cdErr) || cdErr.Expected != 8 || cdErr.Actual != 7 {
+		t.Fatalf("generated actual error \"%v\" (expected check digit 7, expected 8)", err)
+	}
+
+	err = ValidateSplit("3222861R", "8")
+	if !errors.As(err, &icErr) || icErr.Index != 7 || icErr.Rune != 'R' {
+		t.Fatalf("generated actual error \"%v\" (expected invalid character 'R' at index 7)", err)
+	}
+
+	err = ValidateSplit("32228618", "R")
+	if !errors.As(err, &icErr) || icErr.Index != 8 || icErr.Rune != 'R' {
+		t.Fatalf("generated actual error \"%v\" (expected invalid character 'R' at index 8)", err)
+	}
+}
+
+func TestSplitRTN(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedPrefix     string
+		expectedCheckDigit int
+		expectedError      error
+	}{
+		{"1234", "", 0, ErrIncorrectLength},
+		{"R22286188", "", 0, ErrInvalidCharacter},
+		{"123456789", "", 0, ErrChecksumMismatch},
+		{"322286188", "32228618", 8, nil},
+		{"021200025", "02120002", 5, nil},
+		{"000000000", "00000000", 0, nil},
+	}
+
+	for _, test := range tests {
+		prefix, checkDigit, err := SplitRTN(test.input)
+		if prefix != test.expectedPrefix || checkDigit != test.expectedCheckDigit || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", %d, \"%v\" (expected \"%s\", %d, \"%v\")",
+				test.input,
+				prefix,
+				checkDigit,
+				err,
+				test.expectedPrefix,
+				test.expectedCheckDigit,
+				test.expectedError,
+			)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"strings"
+)
+
+// micrTransitSymbol is the E-13B transit symbol which encloses RTNs on checks.
+const micrTransitSymbol = '⑆'
+
+// Normalize converts the provided string into a bare 9-digit RTN by removing
+// separators. Spaces, tabs, carriage returns, line feeds, and hyphens are
+// removed wherever they appear, and MICR transit symbols (⑆) are removed from
+// the beginning and end of the string. Any other non-digit character produces
+// an *InvalidCharacterError, which matches ErrInvalidCharacter and reports the
+// character's offset within the provided string. A result which isn't 9 digits
+// long produces ErrIncorrectLength. The check digit is not verified.
+func Normalize(s string) (rtn string, err error) {
+	if rtn, err = stripSeparators(s); err != nil {
+		return "", err
+	}
+
+	if len(rtn) != 9 {
+		return "", ErrIncorrectLength
+	}
+
+	return rtn, nil
+}
+
+// stripSeparators removes separators from the provided string as Normalize
+// does, without checking the length of the result.
+func stripSeparators(s string) (digits string, err error) {
+	var (
+		transit = string(micrTransitSymbol)
+		trimmed = strings.TrimLeft(strings.TrimLeftFunc(s, isSeparator), transit)
+		offset  = len(s) - len(trimmed)
+		b       strings.Builder
+	)
+
+	trimmed = strings.TrimRight(strings.TrimRightFunc(trimmed, isSeparator), transit)
+	b.Grow(9)
+
+	for i, r := range trimmed {
+		if isSeparator(r) {
+			continue
+		}
+
+		if _, ok := runeToDigit(r); !ok {
+			return "", &InvalidCharacterError{Index: offset + i, Rune: r}
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String(), nil
+}
+
+// ValidateLoose normalizes the provided string as Normalize does and then
+// validates the result as Validate does, reporting checksum mismatches with a
+// *ChecksumError.
+func ValidateLoose(s string) (err error) {
+	rtn, err := Normalize(s)
+	if err != nil {
+		return err
+	}
+
+	return Validate(rtn)
+}
+
+// isSeparator determines whether the provided rune is removed by Normalize.
+func isSeparator(r rune) bool {
+	switch r {
+	case ' ', '\t', '\r', '\n', '-':
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedRTN   string
+		expectedError error
+	}{
+		{"021200025", "021200025", nil},
+		{"0212-0002-5", "021200025", nil},
+		{"021 200 025", "021200025", nil},
+		{"\t021200025\r\n", "021200025", nil},
+		{"⑆021200025⑆", "021200025", nil},
+		{" ⑆0212-0002-5⑆ ", "021200025", nil},
+		{"123456789", "123456789", nil},
+		{"02120R025", "", ErrInvalidCharacter},
+		{"021.200.025", "", ErrInvalidCharacter},
+		{"021_200_025", "", ErrInvalidCharacter},
+		{"0212⑆00025", "", ErrInvalidCharacter},
+		{"0212-0002", "", ErrIncorrectLength},
+		{"0212-0002-55", "", ErrIncorrectLength},
+		{"", "", ErrIncorrectLength},
+		{" - ", "", ErrIncorrectLength},
+	}
+
+	for _, test := range tests {
+		rtn, err := Normalize(test.input)
+		if rtn != test.expectedRTN || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input %q generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				rtn,
+				err,
+				test.expectedRTN,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestValidateLoose(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected error
+	}{
+		{"0212-0002-5", nil},
+		{"021 200 025", nil},
+		{"⑆322286188⑆", nil},
+		{"123-456-789", ErrChecksumMismatch},
+		{"02120R025", ErrInvalidCharacter},
+		{"0212-0002", ErrIncorrectLength},
+	}
+
+	var actual error
+	for _, test := range tests {
+		actual = ValidateLoose(test.input)
+		if !errors.Is(actual, test.expected) {
+			t.Fatalf(
+				"input %q generated actual error \"%v\" (expected \"%v\")",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
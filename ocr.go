@@ -0,0 +1,135 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"unicode/utf8"
+)
+
+// DefaultMaxOCRSubstitutions is the greatest number of characters which
+// ValidateOCR and GetMissingDigitOCR will correct unless configured otherwise.
+const DefaultMaxOCRSubstitutions = 2
+
+// ocrConfig holds the configuration built from a set of OCROptions.
+type ocrConfig struct {
+	maxSubstitutions int
+}
+
+// OCROption configures the correction of OCR confusions.
+type OCROption func(*ocrConfig)
+
+// MaxSubstitutions sets the greatest number of characters which may be
+// corrected. Input which needs more corrections is rejected. A negative n is
+// treated as 0.
+func MaxSubstitutions(n int) OCROption {
+	return func(c *ocrConfig) {
+		if n < 0 {
+			n = 0
+		}
+		c.maxSubstitutions = n
+	}
+}
+
+// ocrDigit returns the digit commonly confused with the provided character by
+// OCR, if any. The table is deliberately small: 'O' and 'o' for 0, 'I' and 'l'
+// for 1, 'S' for 5, and 'B' for 8.
+func ocrDigit(c byte) (digit byte, ok bool) {
+	switch c {
+	case 'O', 'o':
+		return '0', true
+	case 'I', 'l':
+		return '1', true
+	case 'S':
+		return '5', true
+	case 'B':
+		return '8', true
+	}
+
+	return 0, false
+}
+
+// ValidateOCR corrects characters commonly confused with digits by OCR in the
+// provided RTN and validates the result as Parse does, returning the corrected
+// RTN. 'O' and 'o' are read as 0, 'I' and 'l' as 1, 'S' as 5, and 'B' as 8; no
+// other correction is made. Input requiring more than
+// DefaultMaxOCRSubstitutions corrections, or the number set by
+// MaxSubstitutions, is rejected with an *InvalidCharacterError for the first
+// character which would have been corrected. A corrected RTN which fails the
+// checksum produces a *ChecksumError.
+func ValidateOCR(rtn string, opts ...OCROption) (corrected string, err error) {
+	if len(rtn) != 9 {
+		return "", ErrIncorrectLength
+	}
+
+	if corrected, err = correctOCR(rtn, 0, opts); err != nil {
+		return "", err
+	}
+
+	if err = Validate(corrected); err != nil {
+		return "", err
+	}
+
+	return corrected, nil
+}
+
+// GetMissingDigitOCR corrects characters commonly confused with digits by OCR
+// in the provided RTN, as ValidateOCR does, and then calculates a single
+// unknown digit marked by the character 'X' as GetMissingDigit does.
+func GetMissingDigitOCR(rtn string, opts ...OCROption) (digit int, err error) {
+	if len(rtn) != 9 {
+		return 0, ErrIncorrectLength
+	}
+
+	corrected, err := correctOCR(rtn, 'X', opts)
+	if err != nil {
+		return 0, err
+	}
+
+	return GetMissingDigit(corrected)
+}
+
+// correctOCR replaces the OCR confusions within the provided input with their
+// digits. Digits and the provided placeholder, if non-zero, are kept as they
+// are.
+func correctOCR(s string, placeholder byte, opts []OCROption) (corrected string, err error) {
+	var c = ocrConfig{maxSubstitutions: DefaultMaxOCRSubstitutions}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	var (
+		b             []byte
+		substitutions int
+		first         int
+	)
+
+	for i := 0; i < len(s); i++ {
+		if isDigitByte(s[i]) || (placeholder != 0 && s[i] == placeholder) {
+			continue
+		}
+
+		digit, ok := ocrDigit(s[i])
+		if !ok {
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			return "", &InvalidCharacterError{Index: i, Rune: r}
+		}
+
+		if b == nil {
+			b, first = []byte(s), i
+		}
+		b[i] = digit
+		substitutions++
+	}
+
+	if b == nil {
+		return s, nil
+	}
+
+	if substitutions > c.maxSubstitutions {
+		return "", &InvalidCharacterError{Index: first, Rune: rune(s[first])}
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateOCR(t *testing.T) {
+	tests := []struct {
+		input          string
+		opts           []OCROption
+		expectedOutput string
+		expectedError  error
+	}{
+		{"1234", nil, "", ErrIncorrectLength},
+		{"0212O00025", nil, "", ErrIncorrectLength},
+		{"021200025", nil, "021200025", nil},
+		{"O21200025", nil, "021200025", nil},
+		{"o2l200025", nil, "021200025", nil},
+		{"O2I200025", nil, "021200025", nil},
+		{"3222B6lBB", nil, "", ErrInvalidCharacter},
+		{"3222B6lBB", []OCROption{MaxSubstitutions(4)}, "322286188", nil},
+		{"O2120002S", nil, "021200025", nil},
+
+		// Characters outside the confusion table are never corrected
+		{"0212Z0025", nil, "", ErrInvalidCharacter},
+		{"0212é002", nil, "", ErrInvalidCharacter},
+		{"02120002X", nil, "", ErrInvalidCharacter},
+
+		// Corrections beyond the configured limit are rejected
+		{"O2l2OO025", nil, "", ErrInvalidCharacter},
+		{"O2l2OO025", []OCROption{MaxSubstitutions(4)}, "021200025", nil},
+		{"O21200025", []OCROption{MaxSubstitutions(0)}, "", ErrInvalidCharacter},
+		{"O21200025", []OCROption{MaxSubstitutions(-1)}, "", ErrInvalidCharacter},
+
+		// A corrected RTN must still pass the checksum
+		{"O21200026", nil, "", ErrChecksumMismatch},
+		{"O21200B25", nil, "", ErrChecksumMismatch},
+	}
+
+	for _, test := range tests {
+		output, err := ValidateOCR(test.input, test.opts...)
+		if output != test.expectedOutput || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				output,
+				err,
+				test.expectedOutput,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestGetMissingDigitOCR(t *testing.T) {
+	tests := []struct {
+		input          string
+		opts           []OCROption
+		expectedOutput int
+		expectedError  error
+	}{
+		{"1234", nil, 0, ErrIncorrectLength},
+		{"02120002X", nil, 5, nil},
+		{"O2120002X", nil, 5, nil},
+		{"OX1200025", nil, 2, nil},
+		{"O2l2X0025", nil, 0, nil},
+		{"O2l2XO025", nil, 0, ErrInvalidCharacter},
+		{"O2l2XO025", []OCROption{MaxSubstitutions(3)}, 0, nil},
+		{"O2l2XOO2X", []OCROption{MaxSubstitutions(4)}, 0, ErrTooManyMissingDigits},
+		{"O21200025", nil, 0, ErrNoMissingDigits},
+		{"0212Z002X", nil, 0, ErrInvalidCharacter},
+
+		// Only an uppercase 'X' marks the missing digit
+		{"O2120002x", nil, 0, ErrInvalidCharacter},
+	}
+
+	for _, test := range tests {
+		output, err := GetMissingDigitOCR(test.input, test.opts...)
+		if output != test.expectedOutput || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %d, \"%v\" (expected %d, \"%v\")",
+				test.input,
+				output,
+				err,
+				test.expectedOutput,
+				test.expectedError,
+			)
+		}
+	}
+}
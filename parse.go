@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+// RTN is a validated ABA routing transit number. The zero value represents the
+// absence of an RTN; its accessors return empty strings and a check digit of
+// -1.
+type RTN struct {
+	value string
+}
+
+// Parse validates the provided RTN in MICR format and returns it as an RTN.
+// Invalid characters are reported with an *InvalidCharacterError and checksum
+// mismatches with a *ChecksumError, which match ErrInvalidCharacter and
+// ErrChecksumMismatch respectively.
+func Parse(s string) (rtn RTN, err error) {
+	if err = Validate(s); err != nil {
+		return RTN{}, err
+	}
+
+	return RTN{value: s}, nil
+}
+
+// IsZero determines whether the RTN is the zero value.
+func (r RTN) IsZero() bool {
+	return r.value == ""
+}
+
+// FedRoutingSymbol returns the Federal Reserve routing symbol, which is made up
+// of the first four digits of the RTN.
+func (r RTN) FedRoutingSymbol() string {
+	if r.IsZero() {
+		return ""
+	}
+
+	return r.value[:4]
+}
+
+// InstitutionIdentifier returns the ABA institution identifier, which is made
+// up of the fifth through eighth digits of the RTN.
+func (r RTN) InstitutionIdentifier() string {
+	if r.IsZero() {
+		return ""
+	}
+
+	return r.value[4:8]
+}
+
+// CheckDigit returns the check digit, which is the final digit of the RTN.
+func (r RTN) CheckDigit() int {
+	if r.IsZero() {
+		return -1
+	}
+
+	return int(r.value[8] - '0')
+}
+
+// String returns the RTN in its canonical 9-digit form.
+func (r RTN) String() string {
+	return r.value
+}
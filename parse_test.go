@@ -0,0 +1,76 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input                         string
+		expectedFedRoutingSymbol      string
+		expectedInstitutionIdentifier string
+		expectedCheckDigit            int
+		expectedError                 error
+	}{
+		{"asdf", "", "", -1, ErrIncorrectLength},
+		{"0123456789", "", "", -1, ErrIncorrectLength},
+		{"R00000000", "", "", -1, ErrInvalidCharacter},
+		{"123456789", "", "", -1, ErrChecksumMismatch},
+		{"322286188", "3222", "8618", 8, nil},
+		{"021200025", "0212", "0002", 5, nil},
+		{"026014601", "0260", "1460", 1, nil},
+		{"000000000", "0000", "0000", 0, nil},
+	}
+
+	for _, test := range tests {
+		rtn, err := Parse(test.input)
+		if !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual error \"%v\" (expected \"%v\")",
+				test.input,
+				err,
+				test.expectedError,
+			)
+		}
+
+		if rtn.FedRoutingSymbol() != test.expectedFedRoutingSymbol ||
+			rtn.InstitutionIdentifier() != test.expectedInstitutionIdentifier ||
+			rtn.CheckDigit() != test.expectedCheckDigit {
+			t.Fatalf(
+				"input \"%s\" generated actual components \"%s\", \"%s\", %d (expected \"%s\", \"%s\", %d)",
+				test.input,
+				rtn.FedRoutingSymbol(),
+				rtn.InstitutionIdentifier(),
+				rtn.CheckDigit(),
+				test.expectedFedRoutingSymbol,
+				test.expectedInstitutionIdentifier,
+				test.expectedCheckDigit,
+			)
+		}
+
+		if err != nil {
+			if !rtn.IsZero() {
+				t.Fatalf("input \"%s\" generated a non-zero RTN alongside an error", test.input)
+			}
+			continue
+		}
+
+		if rtn.String() != test.input || fmt.Sprint(rtn) != test.input {
+			t.Fatalf("input \"%s\" generated actual string \"%s\"", test.input, rtn)
+		}
+	}
+}
+
+func TestRTNZeroValue(t *testing.T) {
+	var rtn RTN
+	if !rtn.IsZero() || rtn.String() != "" || rtn.FedRoutingSymbol() != "" ||
+		rtn.InstitutionIdentifier() != "" || rtn.CheckDigit() != -1 {
+		t.Fatalf("zero RTN generated unexpected components")
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrMalformedRecord indicates that a record of a participant directory does
+// not match the documented layout.
+var ErrMalformedRecord = errors.New("malformed record")
+
+// RecordError describes a record of a participant directory which could not be
+// decoded.
+type RecordError struct {
+	Line  int    // Line is the one-based line number of the record
+	Field string // Field names the offending field, if any
+	Err   error
+}
+
+// Error returns a description of the record and its problem.
+func (e *RecordError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// RecordErrors is the set of errors encountered while decoding a participant
+// directory with CollectErrors.
+type RecordErrors []*RecordError
+
+// Error returns a description of the first error and the number of others.
+func (e RecordErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no record errors"
+	case 1:
+		return e[0].Error()
+	}
+
+	return fmt.Sprintf("%s (and %d more)", e[0], len(e)-1)
+}
+
+// Unwrap returns each of the errors, so that errors.Is and errors.As match any
+// of them.
+func (e RecordErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// directoryConfig holds the configuration built from a set of
+// DirectoryOptions.
+type directoryConfig struct {
+	collectErrors bool
+}
+
+// DirectoryOption configures the decoding of participant directories.
+type DirectoryOption func(*directoryConfig)
+
+// CollectErrors causes records which can't be decoded to be skipped rather than
+// ending decoding. The records which could be decoded are returned along with a
+// RecordErrors describing those which couldn't.
+func CollectErrors() DirectoryOption {
+	return func(c *directoryConfig) {
+		c.collectErrors = true
+	}
+}
+
+// decodeRecords reads fixed-width records of the provided length, one per
+// line, and passes each to the provided decode function. Blank lines are
+// skipped. Errors returned by decode are wrapped in a *RecordError unless they
+// already are one.
+func decodeRecords(
+	r io.Reader,
+	length int,
+	opts []DirectoryOption,
+	decode func(record string) error,
+) (err error) {
+	var c directoryConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	var (
+		scanner = bufio.NewScanner(r)
+		line    int
+		record  string
+		errs    RecordErrors
+	)
+
+	for scanner.Scan() {
+		line++
+
+		record = strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		if len(record) != length {
+			err = fmt.Errorf("%w: length %d (expected %d)", ErrMalformedRecord, len(record), length)
+		} else {
+			err = decode(record)
+		}
+		if err == nil {
+			continue
+		}
+
+		var recordErr *RecordError
+		if !errors.As(err, &recordErr) {
+			recordErr = &RecordError{Err: err}
+		}
+		recordErr.Line = line
+
+		if !c.collectErrors {
+			return recordErr
+		}
+		errs = append(errs, recordErr)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// fixedField returns the trimmed contents of the provided one-based, inclusive
+// column range of a fixed-width record.
+func fixedField(record string, start, end int) string {
+	return strings.TrimSpace(record[start-1 : end])
+}
+
+// recordRTN validates the provided directory field as an RTN. Fields made up
+// entirely of zeros or spaces produce the zero value when optional is true.
+func recordRTN(value, field string, optional bool) (rtn RTN, err error) {
+	if optional && strings.Trim(value, "0 ") == "" {
+		return RTN{}, nil
+	}
+
+	if rtn, err = Parse(value); err != nil {
+		return RTN{}, &RecordError{Field: field, Err: err}
+	}
+
+	return rtn, nil
+}
+
+// recordDate parses the provided directory field as a date in the provided
+// layout. Fields made up entirely of zeros or spaces produce the zero time.
+func recordDate(value, layout, field string) (date time.Time, err error) {
+	if strings.Trim(value, "0 ") == "" {
+		return time.Time{}, nil
+	}
+
+	if date, err = time.Parse(layout, value); err != nil {
+		return time.Time{}, &RecordError{
+			Field: field,
+			Err:   fmt.Errorf("%w: invalid date %q", ErrMalformedRecord, value),
+		}
+	}
+
+	return date, nil
+}
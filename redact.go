@@ -0,0 +1,210 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// defaultMaskRune is the rune substituted for hidden characters when a
+// RedactionPolicy does not specify one.
+const defaultMaskRune = '*'
+
+// RedactionPolicy describes how much of an RTN is safe to show in logs, error
+// messages, and reports. Characters which are not kept are replaced with the
+// mask rune.
+type RedactionPolicy struct {
+	// KeepPrefix is the number of leading characters left visible.
+	KeepPrefix int
+
+	// KeepSuffix is the number of trailing characters left visible.
+	KeepSuffix int
+
+	// MaskRune replaces each hidden character. If unset, or set to a digit in
+	// any script, '*' is used instead.
+	MaskRune rune
+
+	// FullRedact hides every character regardless of KeepPrefix and KeepSuffix.
+	FullRedact bool
+}
+
+// DefaultRedactionPolicy is the recommended policy for RTNs echoed in logs,
+// errors, and reports. It leaves the first four and the last two characters
+// visible. Mask keeps its own default of the last four digits unless given this
+// policy with WithRedactionPolicy.
+var DefaultRedactionPolicy = RedactionPolicy{
+	KeepPrefix: 4,
+	KeepSuffix: 2,
+	MaskRune:   defaultMaskRune,
+}
+
+// Redact applies the policy to the provided value. The value need not be a
+// valid RTN. If the policy would leave the entire value visible, the entire
+// value is masked instead. A policy with FullRedact set never produces output
+// containing a digit.
+func (p RedactionPolicy) Redact(s string) string {
+	var (
+		runes      = []rune(s)
+		maskRune   = p.maskRune()
+		keepPrefix = p.KeepPrefix
+		keepSuffix = p.KeepSuffix
+		sb         strings.Builder
+		i          int
+		r          rune
+	)
+
+	if keepPrefix < 0 {
+		keepPrefix = 0
+	}
+	if keepSuffix < 0 {
+		keepSuffix = 0
+	}
+
+	// Never leave the entire value visible
+	if p.FullRedact || keepPrefix+keepSuffix >= len(runes) {
+		keepPrefix, keepSuffix = 0, 0
+	}
+
+	sb.Grow(len(s))
+	for i, r = range runes {
+		if i < keepPrefix || i >= len(runes)-keepSuffix {
+			sb.WriteRune(r)
+			continue
+		}
+
+		sb.WriteRune(maskRune)
+	}
+
+	return sb.String()
+}
+
+// RedactError wraps the provided error, produced from the provided input, in a
+// *RedactedError which echoes the input redacted under the policy. An empty
+// input is not echoed, and a nil error produces nil.
+func (p RedactionPolicy) RedactError(input string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if input != "" {
+		input = p.Redact(input)
+	}
+
+	return &RedactedError{Input: input, Err: err, policy: p}
+}
+
+// maskRune returns the rune used to replace hidden characters.
+func (p RedactionPolicy) maskRune() rune {
+	if p.MaskRune == 0 || unicode.IsDigit(p.MaskRune) {
+		return defaultMaskRune
+	}
+
+	return p.MaskRune
+}
+
+// defaultMaskSuffix is the number of trailing digits left visible by Mask
+// unless configured otherwise.
+const defaultMaskSuffix = 4
+
+// maskConfig holds the configuration built from a set of MaskOptions.
+type maskConfig struct {
+	policy RedactionPolicy
+}
+
+// MaskOption configures the masking of RTNs.
+type MaskOption func(*maskConfig)
+
+// WithVisibleSuffix sets the number of trailing digits left visible by Mask.
+// The default is 4. A suffix covering the entire RTN masks every digit.
+func WithVisibleSuffix(n int) MaskOption {
+	return func(c *maskConfig) {
+		c.policy.KeepSuffix = n
+	}
+}
+
+// WithMaskRune sets the rune which replaces hidden digits. The default is '*',
+// which is also used if a digit is provided.
+func WithMaskRune(r rune) MaskOption {
+	return func(c *maskConfig) {
+		c.policy.MaskRune = r
+	}
+}
+
+// WithRedactionPolicy replaces the policy used by Mask, so that masked RTNs
+// agree with other output redacted by the same policy. WithVisibleSuffix and
+// WithMaskRune options provided after it adjust the provided policy.
+func WithRedactionPolicy(p RedactionPolicy) MaskOption {
+	return func(c *maskConfig) {
+		c.policy = p
+	}
+}
+
+// Mask validates the provided RTN and returns it with all but its last 4 digits
+// hidden, such as "*****6188", for display in logs and user interfaces. Input
+// which is not a valid RTN is rejected with the same errors as Parse rather
+// than masked, so that masked output is never mistaken for a valid RTN.
+func Mask(rtn string, opts ...MaskOption) (masked string, err error) {
+	if err = Validate(rtn); err != nil {
+		return "", err
+	}
+
+	var c = maskConfig{
+		policy: RedactionPolicy{
+			KeepSuffix: defaultMaskSuffix,
+			MaskRune:   defaultMaskRune,
+		},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c.policy.Redact(rtn), nil
+}
+
+// Redacted holds a valid RTN which is always formatted in its masked form, so
+// that it can be logged or printed with any verb without exposing the full
+// RTN. The zero value formats as an empty string.
+type Redacted struct {
+	rtn string
+}
+
+// NewRedacted validates the provided RTN and wraps it in a Redacted.
+func NewRedacted(rtn string) (r Redacted, err error) {
+	if err = Validate(rtn); err != nil {
+		return Redacted{}, err
+	}
+
+	return Redacted{rtn: rtn}, nil
+}
+
+// Unmask returns the full RTN.
+func (r Redacted) Unmask() string {
+	return r.rtn
+}
+
+// String returns the RTN masked as by Mask with the default options.
+func (r Redacted) String() string {
+	if r.rtn == "" {
+		return ""
+	}
+
+	masked, _ := Mask(r.rtn)
+
+	return masked
+}
+
+// Format implements fmt.Formatter, writing the masked RTN for every verb. The
+// %q verb quotes the masked RTN.
+func (r Redacted) Format(f fmt.State, verb rune) {
+	s := r.String()
+	if verb == 'q' {
+		s = strconv.Quote(s)
+	}
+
+	_, _ = fmt.Fprint(f, s)
+}
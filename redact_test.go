@@ -0,0 +1,240 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestRedactionPolicyRedact(t *testing.T) {
+	tests := []struct {
+		policy   RedactionPolicy
+		input    string
+		expected string
+	}{
+		{DefaultRedactionPolicy, "322286188", "3222***88"},
+		{DefaultRedactionPolicy, "", ""},
+		{DefaultRedactionPolicy, "123456", "******"},
+		{DefaultRedactionPolicy, "12345R789", "1234***89"},
+		{RedactionPolicy{KeepSuffix: 4}, "322286188", "*****6188"},
+		{RedactionPolicy{KeepSuffix: 4, MaskRune: '#'}, "322286188", "#####6188"},
+		{RedactionPolicy{KeepSuffix: 4, MaskRune: '7'}, "322286188", "*****6188"},
+		{RedactionPolicy{KeepSuffix: 4, MaskRune: '٣'}, "322286188", "*****6188"},
+		{RedactionPolicy{KeepPrefix: -1, KeepSuffix: 2}, "322286188", "*******88"},
+		{RedactionPolicy{KeepPrefix: 9}, "322286188", "*********"},
+		{RedactionPolicy{KeepPrefix: 4, KeepSuffix: 2, FullRedact: true}, "322286188", "*********"},
+	}
+
+	var actual string
+	for _, test := range tests {
+		actual = test.policy.Redact(test.input)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\" (expected \"%s\")",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestRedactionPolicyFullRedactOmitsDigits(t *testing.T) {
+	policies := []RedactionPolicy{
+		{FullRedact: true},
+		{FullRedact: true, KeepPrefix: 9, KeepSuffix: 9},
+		{FullRedact: true, MaskRune: '0'},
+		{FullRedact: true, MaskRune: '٣'},
+		{FullRedact: true, MaskRune: '９'},
+	}
+	inputs := []string{"322286188", "03110064X", "0123456789", "1", "0212-0002-5"}
+
+	var actual string
+	for _, policy := range policies {
+		for _, input := range inputs {
+			actual = policy.Redact(input)
+			if strings.IndexFunc(actual, unicode.IsDigit) >= 0 {
+				t.Fatalf(
+					"input \"%s\" generated output containing digits \"%s\"",
+					input,
+					actual,
+				)
+			}
+		}
+	}
+}
+
+func TestRedactionPolicyRedactError(t *testing.T) {
+	tests := []struct {
+		policy   RedactionPolicy
+		input    string
+		err      error
+		expected string
+	}{
+		{DefaultRedactionPolicy, "123456789", Validate("123456789"), "1234***89: checksum mismatch: checksum remainder 9"},
+		{DefaultRedactionPolicy, "", Validate("123456789"), "checksum mismatch: checksum remainder 9"},
+		{RedactionPolicy{FullRedact: true}, "123456789", Validate("123456789"), "*********: checksum mismatch: checksum remainder *"},
+		{RedactionPolicy{FullRedact: true, MaskRune: '#'}, "02120O025", Validate("02120O025"), "#########: invalid character 'O' at index #"},
+		{RedactionPolicy{FullRedact: true}, "0212", Validate("0212"), "****: incorrect length"},
+	}
+
+	var actual error
+	for _, test := range tests {
+		actual = test.policy.RedactError(test.input, test.err)
+		if actual.Error() != test.expected || !errors.Is(actual, test.err) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%v\" (expected \"%s\")",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+
+	var checksumErr *ChecksumError
+	if !errors.As(DefaultRedactionPolicy.RedactError("123456789", Validate("123456789")), &checksumErr) || checksumErr.Got != 9 {
+		t.Fatalf("redacted error does not wrap the checksum error")
+	}
+
+	if DefaultRedactionPolicy.RedactError("322286188", nil) != nil {
+		t.Fatalf("redacting a nil error generated an error")
+	}
+}
+
+func TestRedactionPolicyRedactErrorFullRedactOmitsDigits(t *testing.T) {
+	type echo struct {
+		input string
+		err   error
+	}
+
+	var (
+		policy = RedactionPolicy{FullRedact: true}
+		echoes = []echo{
+			{"02120002", ValidateEntryRDFI("02120002", "6")},
+			{"021200025", &RecordError{Line: 12, Field: "routing number", Err: ErrChecksumMismatch}},
+		}
+	)
+
+	for _, input := range []string{"322286188", "123456789", "02120O025", "0212", "02120002٣", "0212-0002-5"} {
+		_, err := GetMissingDigit(input)
+		echoes = append(
+			echoes,
+			echo{input, Validate(input)},
+			echo{input, ValidateAll(input)},
+			echo{input, ValidateLoose(input)},
+			echo{input, err},
+		)
+	}
+
+	for _, e := range echoes {
+		if e.err == nil {
+			continue
+		}
+
+		actual := policy.RedactError(e.input, e.err).Error()
+		if strings.IndexFunc(actual, unicode.IsDigit) >= 0 {
+			t.Fatalf("error \"%v\" generated output containing digits \"%s\"", e.err, actual)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		input          string
+		opts           []MaskOption
+		expectedOutput string
+		expectedError  error
+	}{
+		{"1234", nil, "", ErrIncorrectLength},
+		{"3222B6188", nil, "", ErrInvalidCharacter},
+		{"322286189", nil, "", ErrChecksumMismatch},
+		{"322286188", nil, "*****6188", nil},
+		{"322286188", []MaskOption{WithVisibleSuffix(2)}, "*******88", nil},
+		{"322286188", []MaskOption{WithVisibleSuffix(0)}, "*********", nil},
+		{"322286188", []MaskOption{WithVisibleSuffix(9)}, "*********", nil},
+		{"322286188", []MaskOption{WithMaskRune('•')}, "•••••6188", nil},
+		{"322286188", []MaskOption{WithMaskRune('0')}, "*****6188", nil},
+		{"322286188", []MaskOption{WithRedactionPolicy(DefaultRedactionPolicy)}, "3222***88", nil},
+		{"322286188", []MaskOption{WithRedactionPolicy(RedactionPolicy{FullRedact: true})}, "*********", nil},
+		{
+			"322286188",
+			[]MaskOption{WithRedactionPolicy(RedactionPolicy{KeepPrefix: 2, MaskRune: '#'}), WithVisibleSuffix(1)},
+			"32######8",
+			nil,
+		},
+		{"322286189", []MaskOption{WithRedactionPolicy(DefaultRedactionPolicy)}, "", ErrChecksumMismatch},
+	}
+
+	for _, test := range tests {
+		output, err := Mask(test.input, test.opts...)
+		if output != test.expectedOutput || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				output,
+				err,
+				test.expectedOutput,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestNewRedacted(t *testing.T) {
+	if _, err := NewRedacted("322286189"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("generated actual error \"%v\" (expected \"%v\")", err, ErrChecksumMismatch)
+	}
+
+	r, err := NewRedacted("322286188")
+	if err != nil {
+		t.Fatalf("generated unexpected error \"%v\"", err)
+	}
+	if r.Unmask() != "322286188" {
+		t.Fatalf("generated actual unmasked output \"%s\" (expected \"%s\")", r.Unmask(), "322286188")
+	}
+}
+
+func TestRedactedFormat(t *testing.T) {
+	r, err := NewRedacted("322286188")
+	if err != nil {
+		t.Fatalf("generated unexpected error \"%v\"", err)
+	}
+
+	tests := []struct {
+		format   string
+		value    interface{}
+		expected string
+	}{
+		{"%s", r, "*****6188"},
+		{"%v", r, "*****6188"},
+		{"%+v", r, "*****6188"},
+		{"%#v", r, "*****6188"},
+		{"%q", r, "\"*****6188\""},
+		{"%x", r, "*****6188"},
+		{"%d", r, "*****6188"},
+		{"%v", &r, "*****6188"},
+		{"%v", struct{ RTN Redacted }{r}, "{*****6188}"},
+		{"%v", []Redacted{r}, "[*****6188]"},
+		{"%s", Redacted{}, ""},
+		{"%q", Redacted{}, "\"\""},
+	}
+
+	var actual string
+	for _, test := range tests {
+		actual = fmt.Sprintf(test.format, test.value)
+		if actual != test.expected {
+			t.Fatalf(
+				"format \"%s\" generated actual output \"%s\" (expected \"%s\")",
+				test.format,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrNotRepairable indicates that input is not eligible to have its leading
+// zeros restored, either because it is not made up of 7 to 9 digits or because
+// it contains a character other than a digit.
+var ErrNotRepairable = errors.New("not repairable")
+
+// minRepairableLength is the length of the shortest input which may have its
+// leading zeros restored.
+const minRepairableLength = 7
+
+// RepairLeadingZeros restores the leading zeros lost from RTNs which have
+// passed through numeric fields, such as those of spreadsheets, by left-padding
+// input of 7 or 8 digits with zeros to 9 digits. The result is validated and
+// returned; 9-digit input is validated as is. Shorter input, longer input, and
+// input containing anything other than digits is more likely corrupt than
+// truncated, and produces ErrNotRepairable wrapping ErrIncorrectLength or an
+// *InvalidCharacterError. Input which is eligible but fails the checksum once
+// padded produces a *ChecksumError instead. Validate never pads its input.
+func RepairLeadingZeros(s string) (rtn string, err error) {
+	if len(s) < minRepairableLength || len(s) > 9 {
+		return "", fmt.Errorf("%w: %w", ErrNotRepairable, ErrIncorrectLength)
+	}
+
+	if i := indexNonDigit(s); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(s[i:])
+		return "", fmt.Errorf("%w: %w", ErrNotRepairable, &InvalidCharacterError{Index: i, Rune: r})
+	}
+
+	rtn = padLeadingZeros(s, 9)
+	if err = Validate(rtn); err != nil {
+		return "", err
+	}
+
+	return rtn, nil
+}
+
+// indexNonDigit returns the byte index of the first character in the provided
+// string which is not a digit, or -1 if every character is a digit.
+func indexNonDigit(s string) int {
+	for i := 0; i < len(s); i++ {
+		if !isDigitByte(s[i]) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRepairLeadingZeros(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOutput string
+		expectedError  error
+	}{
+		{"026014601", "026014601", nil},
+		{"26014601", "026014601", nil},
+		{"1000009", "001000009", nil},
+		{"01000009", "001000009", nil},
+
+		// Eligible input which fails the checksum once padded
+		{"1000015", "", ErrChecksumMismatch},
+		{"26014602", "", ErrChecksumMismatch},
+		{"123456789", "", ErrChecksumMismatch},
+
+		// Input which is not eligible for padding
+		{"", "", ErrIncorrectLength},
+		{"100009", "", ErrIncorrectLength},
+		{"0260146010", "", ErrIncorrectLength},
+		{"2601460R", "", ErrInvalidCharacter},
+		{" 6014601", "", ErrInvalidCharacter},
+		{"2601-4601", "", ErrInvalidCharacter},
+	}
+
+	for _, test := range tests {
+		output, err := RepairLeadingZeros(test.input)
+		if output != test.expectedOutput || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				output,
+				err,
+				test.expectedOutput,
+				test.expectedError,
+			)
+		}
+
+		// Only ineligible input is reported as not repairable
+		notRepairable := test.expectedError != nil && test.expectedError != ErrChecksumMismatch
+		if errors.Is(err, ErrNotRepairable) != notRepairable {
+			t.Fatalf(
+				"input \"%s\" generated actual error \"%v\" (expected ErrNotRepairable: %t)",
+				test.input,
+				err,
+				notRepairable,
+			)
+		}
+	}
+}
+
+func TestRepairLeadingZerosPosition(t *testing.T) {
+	_, err := RepairLeadingZeros("2601460R")
+
+	var icErr *InvalidCharacterError
+	if !errors.As(err, &icErr) || icErr.Index != 7 || icErr.Rune != 'R' {
+		t.Fatalf("generated actual error \"%v\" (expected invalid character 'R' at index 7)", err)
+	}
+}
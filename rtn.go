@@ -13,7 +13,9 @@
 package rtnutil
 
 import (
+	"bytes"
 	"errors"
+	"unicode/utf8"
 )
 
 // ErrIncorrectLength indicates that an RTN is not the correct length of 9
@@ -41,31 +43,46 @@ var ErrNoMissingDigits = errors.New("no missing digits")
 var checksumMultipliers = []int{3, 7, 1}
 
 // Validate determins whether a provided RTN is in valid MICR format with a
-// correct check digit.
+// correct check digit. Invalid characters are reported with an
+// *InvalidCharacterError and checksum mismatches with a *ChecksumError, which
+// match ErrInvalidCharacter and ErrChecksumMismatch respectively.
 func Validate(rtn string) (err error) {
 	// MICR RTNs are 9 digits
 	if len(rtn) != 9 {
 		return ErrIncorrectLength
 	}
 
-	var (
-		i         int
-		digitRune rune
-		digit     int
-		ok        bool
-		checksum  int
-	)
+	// The conversion doesn't escape, so the RTN is copied to the stack rather
+	// than allocated
+	if err = validateBytes([]byte(rtn)); err != nil {
+		return detailError(rtn, err)
+	}
 
-	// Iterate over each character in the string
-	for i, digitRune = range rtn {
-		// Attempt to convert the character to a digit
-		digit, ok = runeToDigit(digitRune)
-		if !ok {
-			return ErrInvalidCharacter
-		}
+	return nil
+}
 
-		// Multiply the digit by its respective multiplier and add to the checksum
-		checksum += digit * checksumMultipliers[i%3]
+// ValidateBytes determines whether a provided RTN is in valid MICR format with
+// a correct check digit, without converting it to a string. It behaves
+// identically to Validate and does not allocate when the RTN is valid.
+func ValidateBytes(rtn []byte) (err error) {
+	if err = validateBytes(rtn); err != nil {
+		return detailError(string(rtn), err)
+	}
+
+	return nil
+}
+
+// validateBytes validates the provided RTN as Validate does, returning the
+// sentinel errors rather than their detailed forms so that it never allocates.
+func validateBytes(rtn []byte) (err error) {
+	// MICR RTNs are 9 digits
+	if len(rtn) != 9 {
+		return ErrIncorrectLength
+	}
+
+	checksum, err := weightedSum(rtn)
+	if err != nil {
+		return err
 	}
 
 	// If the checksum is not evenly divisible by 10, the RTN is invalid
@@ -76,57 +93,112 @@ func Validate(rtn string) (err error) {
 	return nil
 }
 
+// isValid determines whether the provided RTN is valid without allocating an
+// error, for callers which only need the result.
+func isValid(rtn string) bool {
+	return len(rtn) == 9 && validateBytes([]byte(rtn)) == nil
+}
+
+// Checksum calculates the weighted sum of the digits of the provided RTN, which
+// is a multiple of 10 for valid RTNs. Input is checked for length and invalid
+// characters as Validate does, producing the same errors.
+func Checksum(rtn string) (sum int, err error) {
+	// MICR RTNs are 9 digits
+	if len(rtn) != 9 {
+		return 0, ErrIncorrectLength
+	}
+
+	return weightedSum([]byte(rtn))
+}
+
+// ChecksumRemainder calculates the remainder of the weighted sum of the digits
+// of the provided RTN when divided by 10. Valid RTNs have a remainder of 0.
+func ChecksumRemainder(rtn string) (remainder int, err error) {
+	sum, err := Checksum(rtn)
+	if err != nil {
+		return 0, err
+	}
+
+	return sum % 10, nil
+}
+
+// Weight returns the multiplier applied to the digit at the provided zero-based
+// index of an RTN when calculating its checksum. The multipliers repeat the
+// sequence 3, 7, 1. Indexes outside of 0-8 produce 0.
+func Weight(i int) int {
+	if i < 0 || i > 8 {
+		return 0
+	}
+
+	return checksumMultipliers[i%3]
+}
+
+// weightedSum multiplies each of the provided digits by its weight and returns
+// the sum.
+func weightedSum(digits []byte) (sum int, err error) {
+	var c byte
+
+	// Iterate over each byte rather than each rune, so that multi-byte
+	// characters are rejected at their first byte
+	for i := 0; i < len(digits); i++ {
+		c = digits[i]
+		if c < '0' || c > '9' {
+			return 0, ErrInvalidCharacter
+		}
+
+		// Multiply the digit by its respective multiplier and add to the sum
+		sum += int(c-'0') * checksumMultipliers[i%3]
+	}
+
+	return sum, nil
+}
+
 // GetMissingDigit calculates a single unknown digit within the provided RTN
 // Input must be an RTN in MICR format with a single digit replaced by the
-// character 'X'.
+// character 'X'. Invalid characters are reported with an
+// *InvalidCharacterError.
 func GetMissingDigit(rtn string) (digit int, err error) {
 	if len(rtn) != 9 {
 		return 0, ErrIncorrectLength
 	}
 
 	var (
-		i                 int
-		digitRune         rune
-		missingMultiplier int
-		ok                bool
-		checksum          int
+		digits  [9]byte
+		missing int
+		sum     int
+		weight  int
 	)
 
-	// Iterate over each character in the string
-	for i, digitRune = range rtn {
-		// Check for the "missing digit" rune
-		if digitRune == 'X' {
-			// If the missing multiplier has already been set, there are too many
-			// digits missing from the provided RTN
-			if missingMultiplier > 0 {
-				return 0, ErrTooManyMissingDigits
-			}
-
-			// Set the multiplier for the missing digit based on its index
-			missingMultiplier = checksumMultipliers[i%3]
-			continue
-		}
+	// Treat the missing digit as 0 so that the remaining digits can be summed
+	copy(digits[:], rtn)
+	missing = bytes.IndexByte(digits[:], 'X')
+	if missing >= 0 {
+		digits[missing] = '0'
+	}
 
-		// Attempt to convert the character to a digit
-		digit, ok = runeToDigit(digitRune)
-		if !ok {
-			return 0, ErrInvalidCharacter
+	sum, err = weightedSum(digits[:])
+	if err != nil {
+		// A second 'X' ahead of any other invalid character means there are too
+		// many digits missing from the provided RTN
+		i := bytes.IndexFunc(digits[:], func(r rune) bool { return r < '0' || r > '9' })
+		if digits[i] == 'X' {
+			return 0, ErrTooManyMissingDigits
 		}
 
-		// Multiply the digit by its respective multiplier and add to the checksum
-		checksum += digit * checksumMultipliers[i%3]
+		r, _ := utf8.DecodeRuneInString(rtn[i:])
+		return 0, &InvalidCharacterError{Index: i, Rune: r}
 	}
 
-	// If the missing multiplier was never set, no digits were missing from the
-	// provided RTN
-	if missingMultiplier == 0 {
+	// If no 'X' was found, no digits were missing from the provided RTN
+	if missing < 0 {
 		return 0, ErrNoMissingDigits
 	}
 
 	// Check digits 0-8 to see if they satisfy the checksum
-	for i = 0; i < 9; i++ {
-		if (checksum+(missingMultiplier*i))%10 == 0 {
-			return i, nil
+	weight = Weight(missing)
+	for digit = 0; digit < 9; digit++ {
+		if (sum+weight*digit)%10 == 0 {
+			return digit, nil
 		}
 	}
 
@@ -134,30 +206,40 @@ func GetMissingDigit(rtn string) (digit int, err error) {
 	return 9, nil
 }
 
+// ComputeCheckDigit calculates the check digit which completes the provided
+// 8-digit RTN prefix. Input must be exactly 8 digits; the prefix "00000000"
+// produces a check digit of 0.
+func ComputeCheckDigit(prefix string) (digit int, err error) {
+	if len(prefix) != 8 {
+		return 0, ErrIncorrectLength
+	}
+
+	checksum, err := weightedSum([]byte(prefix))
+	if err != nil {
+		return 0, err
+	}
+
+	// The check digit has a multiplier of 1, so it must make up the difference
+	// between the checksum and the next multiple of 10
+	return (10 - checksum%10) % 10, nil
+}
+
+// Complete calculates the check digit for the provided 8-digit RTN prefix and
+// returns the full 9-digit RTN.
+func Complete(prefix string) (rtn string, err error) {
+	digit, err := ComputeCheckDigit(prefix)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + string(rune('0'+digit)), nil
+}
+
 // runeToDigit attempts to convert the provided rune into a digit.
 func runeToDigit(r rune) (digit int, ok bool) {
-	switch r {
-	case '0':
-		return 0, true
-	case '1':
-		return 1, true
-	case '2':
-		return 2, true
-	case '3':
-		return 3, true
-	case '4':
-		return 4, true
-	case '5':
-		return 5, true
-	case '6':
-		return 6, true
-	case '7':
-		return 7, true
-	case '8':
-		return 8, true
-	case '9':
-		return 9, true
-	}
-
-	return 0, false
+	if r < '0' || r > '9' {
+		return 0, false
+	}
+
+	return int(r - '0'), true
 }
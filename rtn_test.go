@@ -4,7 +4,10 @@
 
 package rtnutil
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestValidate(t *testing.T) {
 	tests := []struct {
@@ -15,6 +18,8 @@ func TestValidate(t *testing.T) {
 		{"1234", ErrIncorrectLength},
 		{"0123456789", ErrIncorrectLength},
 		{"R00000000", ErrInvalidCharacter},
+		{"0212é002", ErrInvalidCharacter},
+		{"02120002\x00", ErrInvalidCharacter},
 		{"123456789", ErrChecksumMismatch},
 		{"322286188", nil},
 		{"021200025", nil},
@@ -28,7 +33,7 @@ func TestValidate(t *testing.T) {
 			test.input,
 			func(t *testing.T) {
 				actual = Validate(test.input)
-				if actual != test.expected {
+				if !errors.Is(actual, test.expected) {
 					t.Fatalf(
 						"input \"%s\" generated actual output \"%t\" (expected \"%t\")",
 						test.input,
@@ -36,6 +41,16 @@ func TestValidate(t *testing.T) {
 						test.expected,
 					)
 				}
+
+				actual = ValidateBytes([]byte(test.input))
+				if !errors.Is(actual, test.expected) {
+					t.Fatalf(
+						"input \"%s\" generated actual bytes output \"%t\" (expected \"%t\")",
+						test.input,
+						actual,
+						test.expected,
+					)
+				}
 			},
 		)
 	}
@@ -52,6 +67,10 @@ func TestGetMissingDigit(t *testing.T) {
 		{"0123456789", 0, ErrIncorrectLength},
 		{"XX2286188", 0, ErrTooManyMissingDigits},
 		{"R22286188", 0, ErrInvalidCharacter},
+		{"X2X28618R", 0, ErrTooManyMissingDigits},
+		{"XR2X86188", 0, ErrInvalidCharacter},
+		{"X22286é8", 0, ErrInvalidCharacter},
+		{"32228618R", 0, ErrInvalidCharacter},
 		{"322286188", 0, ErrNoMissingDigits},
 		{"X22286188", 3, nil},
 		{"3X2286188", 2, nil},
@@ -80,7 +99,7 @@ func TestGetMissingDigit(t *testing.T) {
 			)
 		}
 
-		if actualError != test.expectedError {
+		if !errors.Is(actualError, test.expectedError) {
 			t.Fatalf(
 				"input \"%s\" generated actual error \"%s\" (expected \"%s\")",
 				test.input,
@@ -90,3 +109,163 @@ func TestGetMissingDigit(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeCheckDigit(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedDigit int
+		expectedError error
+	}{
+		{"asdf", 0, ErrIncorrectLength},
+		{"1234567", 0, ErrIncorrectLength},
+		{"123456789", 0, ErrIncorrectLength},
+		{"R2228618", 0, ErrInvalidCharacter},
+		{"3222861X", 0, ErrInvalidCharacter},
+		{"00000000", 0, nil},
+		{"32228618", 8, nil},
+		{"02120002", 5, nil},
+		{"03110064", 9, nil},
+	}
+
+	var (
+		actualDigit int
+		actualError error
+	)
+	for _, test := range tests {
+		actualDigit, actualError = ComputeCheckDigit(test.input)
+		if actualDigit != test.expectedDigit || actualError != test.expectedError {
+			t.Fatalf(
+				"input \"%s\" generated actual output %d, \"%v\" (expected %d, \"%v\")",
+				test.input,
+				actualDigit,
+				actualError,
+				test.expectedDigit,
+				test.expectedError,
+			)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedRTN   string
+		expectedError error
+	}{
+		{"1234", "", ErrIncorrectLength},
+		{"R2228618", "", ErrInvalidCharacter},
+		{"00000000", "000000000", nil},
+	}
+
+	// Every known-good RTN must be reproduced from its first 8 digits
+	for _, rtn := range []string{
+		"322286188", "021200025", "111000025", "026014601", "031100649",
+		"011000015", "091000022", "121000358", "322271627",
+	} {
+		tests = append(tests, struct {
+			input         string
+			expectedRTN   string
+			expectedError error
+		}{rtn[:8], rtn, nil})
+	}
+
+	var (
+		actualRTN   string
+		actualError error
+	)
+	for _, test := range tests {
+		actualRTN, actualError = Complete(test.input)
+		if actualRTN != test.expectedRTN || actualError != test.expectedError {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				actualRTN,
+				actualError,
+				test.expectedRTN,
+				test.expectedError,
+			)
+		}
+
+		if actualError == nil {
+			if actualError = Validate(actualRTN); actualError != nil {
+				t.Fatalf("completed RTN \"%s\" failed validation: %s", actualRTN, actualError)
+			}
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedSum       int
+		expectedRemainder int
+		expectedError     error
+	}{
+		{"1234", 0, 0, ErrIncorrectLength},
+		{"0123456789", 0, 0, ErrIncorrectLength},
+		{"R00000000", 0, 0, ErrInvalidCharacter},
+		{"0212é002", 0, 0, ErrInvalidCharacter},
+		{"322286188", 160, 0, nil},
+		{"021200025", 40, 0, nil},
+		{"000000000", 0, 0, nil},
+		{"123456789", 159, 9, nil},
+		{"999999999", 297, 7, nil},
+	}
+
+	for _, test := range tests {
+		sum, err := Checksum(test.input)
+		if sum != test.expectedSum || err != test.expectedError {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%d\", \"%v\" (expected \"%d\", \"%v\")",
+				test.input,
+				sum,
+				err,
+				test.expectedSum,
+				test.expectedError,
+			)
+		}
+
+		remainder, err := ChecksumRemainder(test.input)
+		if remainder != test.expectedRemainder || err != test.expectedError {
+			t.Fatalf(
+				"input \"%s\" generated actual remainder \"%d\", \"%v\" (expected \"%d\", \"%v\")",
+				test.input,
+				remainder,
+				err,
+				test.expectedRemainder,
+				test.expectedError,
+			)
+		}
+
+		// Validate must agree with the remainder
+		if err == nil && (Validate(test.input) == nil) != (remainder == 0) {
+			t.Fatalf("input \"%s\" generated a remainder which disagrees with Validate", test.input)
+		}
+	}
+}
+
+func TestWeight(t *testing.T) {
+	var expected = []int{0, 3, 7, 1, 3, 7, 1, 3, 7, 1, 0}
+
+	for i := -1; i <= 9; i++ {
+		if actual := Weight(i); actual != expected[i+1] {
+			t.Fatalf("input \"%d\" generated actual weight \"%d\" (expected \"%d\")", i, actual, expected[i+1])
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Validate("026014601")
+	}
+}
+
+func BenchmarkValidateBytes(b *testing.B) {
+	var rtn = []byte("026014601")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = ValidateBytes(rtn)
+	}
+}
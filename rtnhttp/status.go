@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+// Package rtnhttp provides helpers for surfacing rtnutil errors in HTTP
+// responses.
+package rtnhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/schultz-is/rtnutil"
+)
+
+// ProblemContentType is the media type of the documents produced by
+// ProblemJSON.
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix prefixes the stable error code to form a problem type URI.
+const problemTypePrefix = "urn:rtnutil:error:"
+
+// Problem is an RFC 7807 problem document describing an rtnutil error.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Code   string `json:"code"`
+}
+
+// StatusFor returns the HTTP status code appropriate for the provided error.
+// Malformed input produces 400 Bad Request, well-formed input which fails the
+// checksum or uses an unassigned prefix produces 422 Unprocessable Entity, a
+// routing number missing from a directory produces 404 Not Found, a retired
+// routing number whose successors cannot be resolved produces 409 Conflict, and
+// any other error produces 500 Internal Server Error. A nil error produces 200 OK.
+func StatusFor(err error) (status int) {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, rtnutil.ErrIncorrectLength),
+		errors.Is(err, rtnutil.ErrInvalidCharacter),
+		errors.Is(err, rtnutil.ErrTooManyMissingDigits),
+		errors.Is(err, rtnutil.ErrNoMissingDigits),
+		errors.Is(err, rtnutil.ErrInvalidFraction),
+		errors.Is(err, rtnutil.ErrMissingTransitField),
+		errors.Is(err, rtnutil.ErrMultipleTransitFields),
+		errors.Is(err, rtnutil.ErrInvalidMICRLine),
+		errors.Is(err, rtnutil.ErrOutOfRange),
+		errors.Is(err, rtnutil.ErrInvalidPlaceholder),
+		errors.Is(err, rtnutil.ErrNotRepairable):
+		return http.StatusBadRequest
+	case errors.Is(err, rtnutil.ErrChecksumMismatch),
+		errors.Is(err, rtnutil.ErrUnassignedPrefix):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, rtnutil.ErrParticipantNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, rtnutil.ErrSuccessorNotFound),
+		errors.Is(err, rtnutil.ErrSuccessorCycle):
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
+
+// ProblemJSON renders the provided error as an RFC 7807 problem document,
+// returning the document along with the HTTP status code it describes. The
+// document carries the error's stable code and an English message which never
+// includes the input which produced the error. A nil error produces a nil
+// document and 200 OK.
+func ProblemJSON(err error) (body []byte, status int) {
+	status = StatusFor(err)
+	if err == nil {
+		return nil, status
+	}
+
+	code := rtnutil.ErrorCode(err)
+
+	// Marshaling a struct of strings and ints cannot fail
+	body, _ = json.Marshal(Problem{
+		Type:   problemTypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: rtnutil.Message(err, rtnutil.DefaultLanguage),
+		Code:   code,
+	})
+
+	return body, status
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/schultz-is/rtnutil"
+)
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		input    error
+		expected int
+	}{
+		{nil, http.StatusOK},
+		{rtnutil.ErrIncorrectLength, http.StatusBadRequest},
+		{rtnutil.ErrInvalidCharacter, http.StatusBadRequest},
+		{rtnutil.ErrTooManyMissingDigits, http.StatusBadRequest},
+		{rtnutil.ErrNoMissingDigits, http.StatusBadRequest},
+		{rtnutil.ErrInvalidFraction, http.StatusBadRequest},
+		{rtnutil.ErrMissingTransitField, http.StatusBadRequest},
+		{rtnutil.ErrMultipleTransitFields, http.StatusBadRequest},
+		{rtnutil.ErrInvalidMICRLine, http.StatusBadRequest},
+		{rtnutil.ErrOutOfRange, http.StatusBadRequest},
+		{rtnutil.ErrInvalidPlaceholder, http.StatusBadRequest},
+		{rtnutil.ErrNotRepairable, http.StatusBadRequest},
+		{rtnutil.ErrChecksumMismatch, http.StatusUnprocessableEntity},
+		{rtnutil.ErrUnassignedPrefix, http.StatusUnprocessableEntity},
+		{fmt.Errorf("wrapped: %w", rtnutil.ErrChecksumMismatch), http.StatusUnprocessableEntity},
+		{rtnutil.ErrParticipantNotFound, http.StatusNotFound},
+		{rtnutil.ErrSuccessorNotFound, http.StatusConflict},
+		{rtnutil.ErrSuccessorCycle, http.StatusConflict},
+		{fmt.Errorf("%w: 026014601", rtnutil.ErrSuccessorCycle), http.StatusConflict},
+		{errors.New("something else"), http.StatusInternalServerError},
+	}
+
+	var actual int
+	for _, test := range tests {
+		actual = StatusFor(test.input)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%v\" generated actual status %d (expected %d)",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestProblemJSON(t *testing.T) {
+	body, status := ProblemJSON(nil)
+	if body != nil || status != http.StatusOK {
+		t.Fatalf("nil error generated actual output \"%s\", %d", body, status)
+	}
+
+	const input = "123456789"
+	body, status = ProblemJSON(rtnutil.Validate(input))
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("generated actual status %d (expected %d)", status, http.StatusUnprocessableEntity)
+	}
+	if strings.Contains(string(body), input) {
+		t.Fatalf("problem document \"%s\" includes input", body)
+	}
+
+	var problem Problem
+	if err := json.Unmarshal(body, &problem); err != nil {
+		t.Fatalf("decoding problem document failed: %s", err)
+	}
+
+	expected := Problem{
+		Type:   "urn:rtnutil:error:checksum_mismatch",
+		Title:  "Unprocessable Entity",
+		Status: http.StatusUnprocessableEntity,
+		Detail: rtnutil.Message(rtnutil.ErrChecksumMismatch, "en"),
+		Code:   rtnutil.CodeChecksumMismatch,
+	}
+	if problem != expected {
+		t.Fatalf("generated actual problem %+v (expected %+v)", problem, expected)
+	}
+
+	body, status = ProblemJSON(errors.New("database is down"))
+	if status != http.StatusInternalServerError {
+		t.Fatalf("generated actual status %d (expected %d)", status, http.StatusInternalServerError)
+	}
+	if strings.Contains(string(body), "database") {
+		t.Fatalf("problem document \"%s\" includes internal error text", body)
+	}
+}
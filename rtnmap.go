@@ -0,0 +1,190 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+// ErrMalformedMap indicates that data being decoded into an RTNMap is
+// truncated, has trailing bytes, or holds keys which are invalid or out of
+// order.
+var ErrMalformedMap = errors.New("malformed rtn map")
+
+// RTNMap associates values with RTNs. Keys are held as the compact integers
+// produced by Encode in ascending order, so a map uses far less memory than a
+// map[string]T and iterates in a stable order. The zero value is an empty map.
+// An RTNMap is not safe for concurrent use when any goroutine modifies it.
+type RTNMap[T any] struct {
+	keys   []uint32
+	values []T
+}
+
+// search returns the index at which the provided key is, or would be, held.
+func (m *RTNMap[T]) search(key uint32) (i int, found bool) {
+	i = sort.Search(len(m.keys), func(j int) bool { return m.keys[j] >= key })
+
+	return i, i < len(m.keys) && m.keys[i] == key
+}
+
+// Put associates the provided value with the provided RTN, replacing any value
+// already associated with it. The RTN is validated as Encode does, and invalid
+// RTNs produce the same errors.
+func (m *RTNMap[T]) Put(rtn string, value T) (err error) {
+	key, err := Encode(rtn)
+	if err != nil {
+		return err
+	}
+
+	i, found := m.search(key)
+	if found {
+		m.values[i] = value
+		return nil
+	}
+
+	var zero T
+	m.keys = append(m.keys, 0)
+	m.values = append(m.values, zero)
+	copy(m.keys[i+1:], m.keys[i:])
+	copy(m.values[i+1:], m.values[i:])
+	m.keys[i], m.values[i] = key, value
+
+	return nil
+}
+
+// Get returns the value associated with the provided RTN, if any. Invalid RTNs
+// are never present.
+func (m *RTNMap[T]) Get(rtn string) (value T, ok bool) {
+	key, err := Encode(rtn)
+	if err != nil {
+		return value, false
+	}
+
+	if i, found := m.search(key); found {
+		return m.values[i], true
+	}
+
+	return value, false
+}
+
+// Delete removes the provided RTN from the map, reporting whether it was
+// present.
+func (m *RTNMap[T]) Delete(rtn string) (ok bool) {
+	key, err := Encode(rtn)
+	if err != nil {
+		return false
+	}
+
+	i, found := m.search(key)
+	if !found {
+		return false
+	}
+
+	var zero T
+	copy(m.keys[i:], m.keys[i+1:])
+	copy(m.values[i:], m.values[i+1:])
+	m.keys = m.keys[:len(m.keys)-1]
+	m.values[len(m.values)-1] = zero
+	m.values = m.values[:len(m.values)-1]
+
+	return true
+}
+
+// Len returns the number of RTNs in the map.
+func (m *RTNMap[T]) Len() int {
+	return len(m.keys)
+}
+
+// Range calls the provided function for each RTN in the map and its value, in
+// ascending order of RTN, until the function returns false. The map must not be
+// modified during iteration.
+func (m *RTNMap[T]) Range(fn func(rtn string, value T) bool) {
+	for i, key := range m.keys {
+		// Keys are only added by Put, so decoding them cannot fail
+		rtn, _ := Decode(key)
+		if !fn(rtn, m.values[i]) {
+			return
+		}
+	}
+}
+
+// MarshalWith encodes the map using the provided function to encode each
+// value. The encoding is the number of entries as a uvarint, followed by each
+// entry in ascending order of RTN as the 4-byte form produced by
+// RTN.MarshalBinary, the length of the encoded value as a uvarint, and the
+// encoded value. An error from the provided function is returned unchanged.
+func (m *RTNMap[T]) MarshalWith(encode func(T) ([]byte, error)) (data []byte, err error) {
+	var value []byte
+
+	data = binary.AppendUvarint(data, uint64(len(m.keys)))
+	for i, key := range m.keys {
+		if value, err = encode(m.values[i]); err != nil {
+			return nil, err
+		}
+
+		data = binary.BigEndian.AppendUint32(data, key)
+		data = binary.AppendUvarint(data, uint64(len(value)))
+		data = append(data, value...)
+	}
+
+	return data, nil
+}
+
+// UnmarshalWith replaces the contents of the map with those decoded from data
+// produced by MarshalWith, using the provided function to decode each value.
+// Malformed data produces ErrMalformedMap, and an error from the provided
+// function is returned unchanged. If decoding fails, the map is left
+// unchanged.
+func (m *RTNMap[T]) UnmarshalWith(data []byte, decode func([]byte) (T, error)) (err error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)/(binaryLength+1)) {
+		return ErrMalformedMap
+	}
+	data = data[n:]
+
+	var (
+		keys   = make([]uint32, 0, count)
+		values = make([]T, 0, count)
+		key    uint32
+		length uint64
+		value  T
+	)
+
+	for i := uint64(0); i < count; i++ {
+		if len(data) < binaryLength {
+			return ErrMalformedMap
+		}
+
+		key = binary.BigEndian.Uint32(data)
+		if _, err = Decode(key); err != nil || (len(keys) > 0 && key <= keys[len(keys)-1]) {
+			return ErrMalformedMap
+		}
+		data = data[binaryLength:]
+
+		length, n = binary.Uvarint(data)
+		if n <= 0 || length > uint64(len(data)-n) {
+			return ErrMalformedMap
+		}
+		data = data[n:]
+
+		if value, err = decode(data[:length]); err != nil {
+			return err
+		}
+		data = data[length:]
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+
+	if len(data) != 0 {
+		return ErrMalformedMap
+	}
+
+	m.keys, m.values = keys, values
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// riskTier is an example of metadata associated with RTNs.
+type riskTier struct {
+	Tier  int
+	Owner string
+}
+
+func TestRTNMap(t *testing.T) {
+	var m RTNMap[riskTier]
+
+	if _, ok := m.Get("322286188"); ok || m.Len() != 0 {
+		t.Fatalf("zero value map is not empty")
+	}
+
+	puts := []struct {
+		rtn           string
+		value         riskTier
+		expectedError error
+	}{
+		{"322286188", riskTier{1, "payments"}, nil},
+		{"021200025", riskTier{2, "treasury"}, nil},
+		{"111000025", riskTier{3, "payments"}, nil},
+		{"026014601", riskTier{1, "lending"}, nil},
+		{"322286188", riskTier{2, "payments"}, nil},
+		{"123456789", riskTier{}, ErrChecksumMismatch},
+		{"1234", riskTier{}, ErrIncorrectLength},
+	}
+	for _, put := range puts {
+		if err := m.Put(put.rtn, put.value); !errors.Is(err, put.expectedError) {
+			t.Fatalf("input \"%s\" generated actual error \"%v\" (expected \"%v\")", put.rtn, err, put.expectedError)
+		}
+	}
+
+	if m.Len() != 4 {
+		t.Fatalf("map has %d entries (expected 4)", m.Len())
+	}
+	if v, ok := m.Get("322286188"); !ok || v != (riskTier{2, "payments"}) {
+		t.Fatalf("replaced value generated actual output %+v, %t", v, ok)
+	}
+	if _, ok := m.Get("123456789"); ok {
+		t.Fatalf("invalid RTN was found in the map")
+	}
+
+	if !m.Delete("111000025") || m.Delete("111000025") || m.Delete("123456789") {
+		t.Fatalf("deleting generated unexpected results")
+	}
+
+	var actual []string
+	m.Range(func(rtn string, v riskTier) bool {
+		actual = append(actual, rtn+":"+v.Owner)
+		return true
+	})
+	expected := []string{"021200025:treasury", "026014601:lending", "322286188:payments"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("iteration generated actual output %v (expected %v)", actual, expected)
+	}
+
+	var calls int
+	m.Range(func(string, riskTier) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatalf("stopping iteration early generated %d calls (expected 1)", calls)
+	}
+}
+
+func TestRTNMapMarshal(t *testing.T) {
+	var (
+		m      RTNMap[int]
+		encode = func(v int) ([]byte, error) { return []byte(strconv.Itoa(v)), nil }
+		decode = func(b []byte) (int, error) { return strconv.Atoi(string(b)) }
+	)
+
+	for i, rtn := range []string{"322286188", "021200025", "011000015"} {
+		if err := m.Put(rtn, i*100); err != nil {
+			t.Fatalf("input \"%s\" generated unexpected error \"%v\"", rtn, err)
+		}
+	}
+
+	data, err := m.MarshalWith(encode)
+	if err != nil {
+		t.Fatalf("marshaling generated unexpected error \"%v\"", err)
+	}
+
+	// The framing is pinned; changing it would break stored maps
+	expected := []byte{
+		3,
+		0x00, 0xa7, 0xd8, 0xcf, 3, '2', '0', '0',
+		0x01, 0x43, 0x7c, 0x99, 3, '1', '0', '0',
+		0x13, 0x35, 0xb2, 0x6c, 1, '0',
+	}
+	if !reflect.DeepEqual(data, expected) {
+		t.Fatalf("marshaling generated actual output %x (expected %x)", data, expected)
+	}
+
+	var decoded RTNMap[int]
+	if err = decoded.UnmarshalWith(data, decode); err != nil {
+		t.Fatalf("unmarshaling generated unexpected error \"%v\"", err)
+	}
+	if !reflect.DeepEqual(decoded, m) {
+		t.Fatalf("round trip generated actual output %+v (expected %+v)", decoded, m)
+	}
+
+	malformed := [][]byte{
+		nil,
+		{},
+		{1},
+		{1, 0x00, 0xa7, 0xd8, 0xcf},
+		{1, 0x00, 0xa7, 0xd8, 0xcf, 5, '1'},
+		{1, 0x00, 0xa7, 0xd8, 0xce, 1, '1'},
+		{2, 0x13, 0x35, 0xb2, 0x6c, 1, '0', 0x00, 0xa7, 0xd8, 0xcf, 1, '1'},
+		append(append([]byte{}, data...), 0),
+	}
+	for _, input := range malformed {
+		if err = decoded.UnmarshalWith(input, decode); !errors.Is(err, ErrMalformedMap) {
+			t.Fatalf("input %x generated actual error \"%v\" (expected \"%v\")", input, err, ErrMalformedMap)
+		}
+	}
+	if decoded.Len() != 3 {
+		t.Fatalf("failed unmarshaling modified the map")
+	}
+
+	if err = decoded.UnmarshalWith([]byte{1, 0x00, 0xa7, 0xd8, 0xcf, 1, 'x'}, decode); err == nil || errors.Is(err, ErrMalformedMap) {
+		t.Fatalf("value decoding failure generated actual error \"%v\"", err)
+	}
+}
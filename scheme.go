@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+// Scheme describes the structural rules of a family of routing numbers. ABA
+// returns the built-in scheme, and other schemes (such as foreign or internal
+// pseudo-routing numbers) may be provided by implementing this interface.
+type Scheme interface {
+	// Length returns the number of characters in a complete routing number.
+	Length() int
+
+	// Validate determines whether the provided routing number is structurally
+	// valid under the scheme.
+	Validate(s string) error
+
+	// Complete returns the full routing number for the provided prefix, which
+	// contains every character except the check digit.
+	Complete(prefix string) (string, error)
+
+	// Format returns the canonical rendering of the provided routing number.
+	Format(s string) (string, error)
+}
+
+// ABA returns the scheme for American Bankers Association routing transit
+// numbers using the 3-7-1 checksum. Its methods behave as the package-level
+// functions of the same names, which remain specific to ABA RTNs.
+func ABA() Scheme {
+	return abaScheme{}
+}
+
+// abaScheme implements Scheme for ABA RTNs.
+type abaScheme struct{}
+
+// Length returns the number of digits in an ABA RTN.
+func (abaScheme) Length() int {
+	return 9
+}
+
+// Validate determines whether the provided RTN is in valid MICR format with a
+// correct check digit.
+func (abaScheme) Validate(s string) error {
+	return Validate(s)
+}
+
+// Complete appends the check digit to the provided 8-digit RTN prefix.
+func (abaScheme) Complete(prefix string) (rtn string, err error) {
+	return Complete(prefix)
+}
+
+// Format validates the provided RTN and returns it in its canonical 9-digit
+// form.
+func (abaScheme) Format(s string) (rtn string, err error) {
+	if err = Validate(s); err != nil {
+		return "", err
+	}
+
+	return s, nil
+}
+
+// validateScheme validates the provided value under the provided scheme, or
+// under the ABA scheme if it is nil. Loose validation removes separators as
+// Normalize does before validating.
+func validateScheme(s Scheme, value string, loose bool) (err error) {
+	if s == nil {
+		s = abaScheme{}
+	}
+
+	if loose {
+		if value, err = stripSeparators(value); err != nil {
+			return err
+		}
+	}
+
+	return s.Validate(value)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestABAScheme(t *testing.T) {
+	if ABA().Length() != 9 {
+		t.Fatalf("ABA scheme has length %d (expected 9)", ABA().Length())
+	}
+
+	tests := []struct {
+		input            string
+		expectedComplete string
+		expectedError    error
+	}{
+		{"1234", "", ErrIncorrectLength},
+		{"123456789", "", ErrIncorrectLength},
+		{"R2228618", "", ErrInvalidCharacter},
+		{"00000000", "000000000", nil},
+		{"32228618", "322286188", nil},
+		{"02120002", "021200025", nil},
+		{"11100002", "111000025", nil},
+		{"02601460", "026014601", nil},
+		{"03110064", "031100649", nil},
+	}
+
+	var (
+		actual      string
+		actualError error
+	)
+	for _, test := range tests {
+		actual, actualError = ABA().Complete(test.input)
+		if actual != test.expectedComplete || actualError != test.expectedError {
+			t.Fatalf(
+				"input \"%s\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				actual,
+				actualError,
+				test.expectedComplete,
+				test.expectedError,
+			)
+		}
+
+		if actualError != nil {
+			continue
+		}
+
+		if actualError = ABA().Validate(actual); actualError != nil {
+			t.Fatalf("completed RTN \"%s\" failed validation: %s", actual, actualError)
+		}
+
+		actual, actualError = ABA().Format(actual)
+		if actual != test.expectedComplete || actualError != nil {
+			t.Fatalf(
+				"formatting \"%s\" generated actual output \"%s\", \"%v\"",
+				test.expectedComplete,
+				actual,
+				actualError,
+			)
+		}
+	}
+
+	if _, err := ABA().Format("123456789"); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("formatting an invalid RTN generated error \"%v\"", err)
+	}
+}
+
+// sumScheme is a non-ABA scheme used to test scheme options. Its routing
+// numbers are five digits, the last of which is the sum of the others modulo
+// 10.
+type sumScheme struct{}
+
+func (sumScheme) Length() int {
+	return 5
+}
+
+func (s sumScheme) Validate(v string) error {
+	if len(v) != 5 {
+		return ErrIncorrectLength
+	}
+
+	complete, err := s.Complete(v[:4])
+	if err != nil {
+		return err
+	}
+	if complete != v {
+		return ErrChecksumMismatch
+	}
+
+	return nil
+}
+
+func (sumScheme) Complete(prefix string) (string, error) {
+	if len(prefix) != 4 {
+		return "", ErrIncorrectLength
+	}
+
+	var sum int
+	for _, r := range prefix {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidCharacter
+		}
+		sum += int(r - '0')
+	}
+
+	return prefix + string(rune('0'+sum%10)), nil
+}
+
+func (s sumScheme) Format(v string) (string, error) {
+	if err := s.Validate(v); err != nil {
+		return "", err
+	}
+
+	return v, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// LookupService provides lookups in a FedACH participant directory which is
+// kept current from some source, allowing applications to change where the
+// directory comes from without changing how it is used. DirectoryClient
+// serves a directory downloaded over HTTP and DirectoryFile serves one read
+// from a local file.
+type LookupService interface {
+	// Lookup returns the participant with the provided routing number, if any.
+	Lookup(rtn string) (participant ACHParticipant, ok bool)
+
+	// Search returns the participants matching the provided query, as
+	// Directory.Search does.
+	Search(q Query) (participants []ACHParticipant)
+
+	// Refresh reloads the directory from its source if it has changed,
+	// reporting whether it was reloaded.
+	Refresh(ctx context.Context) (changed bool, err error)
+}
+
+// DirectoryFile keeps a Directory current with the FedACH participant
+// directory stored in a local file, such as one downloaded by a scheduled job.
+// It is safe for concurrent use, and lookups in its directory are never blocked
+// by a refresh.
+type DirectoryFile struct {
+	// Path is the location of the FedACH participant directory.
+	Path string
+
+	mu        sync.Mutex
+	modTime   time.Time
+	size      int64
+	directory Directory
+}
+
+// Directory returns the directory kept current by the file. It is empty until
+// the first successful call to Refresh.
+func (f *DirectoryFile) Directory() *Directory {
+	return &f.directory
+}
+
+// Lookup returns the participant with the provided routing number, if any.
+func (f *DirectoryFile) Lookup(rtn string) (participant ACHParticipant, ok bool) {
+	return f.directory.Lookup(rtn)
+}
+
+// Search returns the participants matching the provided query, as
+// Directory.Search does.
+func (f *DirectoryFile) Search(q Query) (participants []ACHParticipant) {
+	return f.directory.Search(q)
+}
+
+// Refresh reads the file, reloading the directory if the file's modification
+// time or size has changed since the last successful refresh, and reports
+// whether it was reloaded. If the file can't be read or decoded, the directory
+// is left unchanged.
+func (f *DirectoryFile) Refresh(ctx context.Context) (changed bool, err error) {
+	if err = ctx.Err(); err != nil {
+		return false, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	if f.directory.load() != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return false, nil
+	}
+
+	if err = f.directory.ReloadFromReader(file); err != nil {
+		return false, err
+	}
+	f.modTime, f.size = info.ModTime(), info.Size()
+
+	return true, nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Ensure each source of directories satisfies LookupService.
+var (
+	_ LookupService = (*DirectoryClient)(nil)
+	_ LookupService = (*DirectoryFile)(nil)
+)
+
+func TestDirectoryFileRefresh(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/FedACHdir.txt")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %s", err)
+	}
+
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(t.TempDir(), "FedACHdir.txt")
+		f    = &DirectoryFile{Path: path}
+	)
+
+	// A missing file is an error
+	if changed, err := f.Refresh(ctx); changed || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("refresh of a missing file generated actual output %t, \"%v\"", changed, err)
+	}
+
+	if err = os.WriteFile(path, fixture, 0o644); err != nil {
+		t.Fatalf("failed to write directory: %s", err)
+	}
+
+	// The first refresh loads the file, and the second finds it unchanged
+	if changed, err := f.Refresh(ctx); !changed || err != nil || f.Directory().Len() != 5 {
+		t.Fatalf("first refresh generated actual output %t, \"%v\"", changed, err)
+	}
+	if changed, err := f.Refresh(ctx); changed || err != nil {
+		t.Fatalf("second refresh generated actual output %t, \"%v\"", changed, err)
+	}
+
+	if p, ok := f.Lookup("026014601"); !ok || p.CustomerName != "EXAMPLE NATIONAL BANK" {
+		t.Fatalf("lookup generated actual output %+v, %t", p, ok)
+	}
+	if n := len(f.Search(Query{State: "NY"})); n != 1 {
+		t.Fatalf("search generated %d participants (expected 1)", n)
+	}
+
+	// A modified file is reloaded, unless it can't be decoded
+	modTime := time.Now().Add(time.Hour)
+	if err = os.WriteFile(path, fixture[:fedACHRecordLength+2], 0o644); err != nil {
+		t.Fatalf("failed to write directory: %s", err)
+	}
+	if err = os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed to modify directory: %s", err)
+	}
+	if changed, err := f.Refresh(ctx); !changed || err != nil || f.Directory().Len() != 1 {
+		t.Fatalf("modified refresh generated actual output %t, \"%v\"", changed, err)
+	}
+
+	if err = os.WriteFile(path, []byte("truncated\n"), 0o644); err != nil {
+		t.Fatalf("failed to write directory: %s", err)
+	}
+	if changed, err := f.Refresh(ctx); changed || !errors.Is(err, ErrMalformedRecord) || f.Directory().Len() != 1 {
+		t.Fatalf("malformed refresh generated actual output %t, \"%v\"", changed, err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if changed, err := f.Refresh(cancelled); changed || !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled refresh generated actual output %t, \"%v\"", changed, err)
+	}
+}
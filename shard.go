@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+// ShardFor returns the shard, in the range [0, n), to which the provided RTN
+// is assigned when work is partitioned into n shards. The assignment is the
+// RTN's 9-digit value, as an unsigned integer, modulo n, and will never change;
+// every producer and consumer which agrees on n agrees on the assignment. If
+// the RTN is not valid or n is less than 1, -1 is returned.
+func ShardFor(rtn string, n int) (shard int) {
+	if n < 1 || !isValid(rtn) {
+		return -1
+	}
+
+	var value uint32
+	for i := 0; i < len(rtn); i++ {
+		value = value*10 + uint32(rtn[i]-'0')
+	}
+
+	return int(value % uint32(n))
+}
+
+// Shard partitions the participants in the directory into n directories, with
+// each participant placed in the directory at the index ShardFor assigns to its
+// routing number. Each shard is built as NewDirectory builds a directory and
+// supports every lookup and search a full directory does; shards with no
+// participants are empty rather than nil. If n is less than 1, nil is
+// returned.
+func (d *Directory) Shard(n int) (shards []*Directory) {
+	if n < 1 {
+		return nil
+	}
+
+	var participants = make([][]ACHParticipant, n)
+	if s := d.load(); s != nil {
+		for _, e := range s.entries {
+			i := ShardFor(e.participant.RoutingNumber.String(), n)
+			participants[i] = append(participants[i], e.participant)
+		}
+	}
+
+	shards = make([]*Directory, n)
+	for i := range shards {
+		shards[i] = NewDirectory(participants[i])
+	}
+
+	return shards
+}
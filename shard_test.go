@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import "testing"
+
+func TestShardFor(t *testing.T) {
+	// These assignments are pinned; changing them would redistribute all
+	// previously partitioned data
+	tests := []struct {
+		rtn      string
+		n        int
+		expected int
+	}{
+		{"322286188", 1, 0},
+		{"322286188", 2, 0},
+		{"322286188", 7, 0},
+		{"322286188", 16, 12},
+		{"322286188", 1000, 188},
+		{"021200025", 9, 3},
+		{"021200025", 16, 9},
+		{"111000025", 16, 9},
+		{"026014601", 16, 9},
+		{"026014601", 64, 9},
+		{"000000000", 16, 0},
+		{"123456789", 16, -1},
+		{"1234", 16, -1},
+		{"322286188", 0, -1},
+		{"322286188", -4, -1},
+	}
+
+	var actual int
+	for _, test := range tests {
+		actual = ShardFor(test.rtn, test.n)
+		if actual != test.expected {
+			t.Fatalf(
+				"input \"%s\", %d generated actual shard %d (expected %d)",
+				test.rtn,
+				test.n,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestDirectoryShard(t *testing.T) {
+	d := loadTestDirectory(t)
+
+	// These assignments are pinned, as those of ShardFor are
+	expected := [][]string{
+		{"322286188"},
+		{"021200025", "026014601", "111000025"},
+		nil,
+		{"011000015"},
+	}
+
+	shards := d.Shard(len(expected))
+	if len(shards) != len(expected) {
+		t.Fatalf("generated %d shards (expected %d)", len(shards), len(expected))
+	}
+
+	for i, shard := range shards {
+		if shard.Len() != len(expected[i]) {
+			t.Fatalf("shard %d has %d participants (expected %d)", i, shard.Len(), len(expected[i]))
+		}
+
+		for _, rtn := range expected[i] {
+			if _, ok := shard.Lookup(rtn); !ok {
+				t.Fatalf("shard %d is missing \"%s\"", i, rtn)
+			}
+			if ShardFor(rtn, len(shards)) != i {
+				t.Fatalf("shard %d holds \"%s\", which ShardFor assigns elsewhere", i, rtn)
+			}
+		}
+	}
+
+	// Shards retain full index functionality
+	if p := shards[1].Search(Query{State: "NY"}); len(p) == 0 {
+		t.Fatalf("shard 1 search generated no participants")
+	}
+
+	if shards = d.Shard(0); shards != nil {
+		t.Fatalf("0 shards generated actual output %v (expected nil)", shards)
+	}
+	if shards = (&Directory{}).Shard(2); len(shards) != 2 || shards[0].Len() != 0 || shards[1].Len() != 0 {
+		t.Fatalf("empty directory generated actual shards %v", shards)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+//go:build go1.21
+
+package rtnutil
+
+import (
+	"log/slog"
+	"strings"
+	"unicode"
+)
+
+// Attr returns a log attribute holding the provided RTN redacted under the
+// policy.
+func (p RedactionPolicy) Attr(key, rtn string) slog.Attr {
+	return slog.String(key, p.Redact(rtn))
+}
+
+// ReplaceAttr redacts RTNs and errors logged through a slog.Handler which uses
+// it as its slog.HandlerOptions.ReplaceAttr. String attributes containing a
+// digit and integer attributes are redacted as Redact does, and error
+// attributes are rendered as RedactError renders them, so that under a policy
+// with FullRedact set none of them contain a digit. Other attributes, such as
+// times, are returned unchanged. Attributes which never hold RTNs can be kept
+// visible by checking their keys before calling ReplaceAttr.
+func (p RedactionPolicy) ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if strings.IndexFunc(a.Value.String(), unicode.IsDigit) >= 0 {
+			return slog.String(a.Key, p.Redact(a.Value.String()))
+		}
+	case slog.KindInt64, slog.KindUint64:
+		return slog.String(a.Key, p.Redact(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, p.RedactError("", err).Error())
+		}
+	}
+
+	return a
+}
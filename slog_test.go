@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+//go:build go1.21
+
+package rtnutil
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactionPolicyReplaceAttr(t *testing.T) {
+	tests := []struct {
+		policy   RedactionPolicy
+		expected string
+	}{
+		{
+			DefaultRedactionPolicy,
+			"level=INFO msg=paid rtn=3222***88 payee=0212*****-5 " +
+				"err=\"checksum mismatch: checksum remainder 9\" amount=**** memo=rent attempt=1234***89\n",
+		},
+		{
+			RedactionPolicy{FullRedact: true},
+			"level=INFO msg=paid rtn=********* payee=*********** " +
+				"err=\"checksum mismatch: checksum remainder *\" amount=**** memo=rent attempt=*********\n",
+		},
+	}
+
+	for _, test := range tests {
+		var (
+			buf    bytes.Buffer
+			logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					// Timestamps are not deterministic
+					if len(groups) == 0 && a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return test.policy.ReplaceAttr(groups, a)
+				},
+			}))
+		)
+
+		logger.Info(
+			"paid",
+			"rtn", "322286188",
+			"payee", "0212-0002-5",
+			"err", Validate("123456789"),
+			"amount", 1250,
+			"memo", "rent",
+			test.policy.Attr("attempt", "123456789"),
+		)
+		if buf.String() != test.expected {
+			t.Fatalf("logging generated actual output %q (expected %q)", buf.String(), test.expected)
+		}
+	}
+}
+
+func TestRedactionPolicyReplaceAttrFullRedact(t *testing.T) {
+	var (
+		buf    bytes.Buffer
+		policy = RedactionPolicy{FullRedact: true}
+		logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return policy.ReplaceAttr(groups, a)
+			},
+		}))
+	)
+
+	for _, rtn := range []string{"322286188", "123456789", "02120O025", "0212 0002 5"} {
+		encoded, _ := Encode(rtn)
+		logger.Warn("invalid rtn "+rtn, "rtn", rtn, "err", ValidateLoose(rtn), "encoded", encoded)
+		logger.Warn("invalid rtn", policy.Attr("rtn", rtn), slog.Group("detail", "err", ValidateAll(rtn)))
+	}
+
+	if strings.ContainsAny(buf.String(), "0123456789") {
+		t.Fatalf("logging generated output containing digits %q", buf.String())
+	}
+}
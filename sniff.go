@@ -0,0 +1,220 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrAmbiguousFormat indicates that the format of some input could not be
+// determined with confidence.
+var ErrAmbiguousFormat = errors.New("ambiguous format")
+
+// FileFormat identifies the layout of a file containing RTNs.
+type FileFormat int
+
+const (
+	// FileFormatUnknown indicates that a format could not be determined.
+	FileFormatUnknown FileFormat = iota
+
+	// FileFormatLines is plain text with one value per line.
+	FileFormatLines
+
+	// FileFormatCSV is comma-separated values.
+	FileFormatCSV
+
+	// FileFormatTSV is tab-separated values.
+	FileFormatTSV
+
+	// FileFormatJSONL is one JSON object per line.
+	FileFormatJSONL
+
+	// FileFormatFixedWidth is fixed-width records with no delimiters.
+	FileFormatFixedWidth
+)
+
+// String returns the name of the format.
+func (f FileFormat) String() string {
+	switch f {
+	case FileFormatLines:
+		return "lines"
+	case FileFormatCSV:
+		return "csv"
+	case FileFormatTSV:
+		return "tsv"
+	case FileFormatJSONL:
+		return "jsonl"
+	case FileFormatFixedWidth:
+		return "fixed-width"
+	}
+
+	return "unknown"
+}
+
+// SniffError describes the evidence considered when the format of some input
+// could not be determined. It matches ErrAmbiguousFormat.
+type SniffError struct {
+	// Candidates lists the formats which remained plausible, if any.
+	Candidates []FileFormat
+
+	// Evidence summarizes the observations made of the sampled input.
+	Evidence string
+}
+
+// Error returns a description of the ambiguity.
+func (e *SniffError) Error() string {
+	return fmt.Sprintf("%s: candidates %v: %s", ErrAmbiguousFormat, e.Candidates, e.Evidence)
+}
+
+// Unwrap returns ErrAmbiguousFormat.
+func (e *SniffError) Unwrap() error {
+	return ErrAmbiguousFormat
+}
+
+const (
+	// sniffSize is the number of bytes inspected when sniffing a format.
+	sniffSize = 4096
+
+	// maxLineValueLength is the longest line permitted in FileFormatLines input.
+	maxLineValueLength = 16
+
+	// minFixedWidthLength is the shortest record permitted in
+	// FileFormatFixedWidth input.
+	minFixedWidthLength = 20
+)
+
+// utf8BOM is the byte order mark which may prefix UTF-8 input.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// SniffFormat inspects the first few kilobytes of the provided reader and
+// determines the format of its contents. The returned reader replays the
+// inspected bytes followed by the remainder of the input, and should be used
+// in place of the provided reader.
+//
+// Input is detected as JSONL when every sampled line is a JSON object, as CSV
+// or TSV when every sampled line contains the same non-zero number of commas or
+// tabs (and not both), as plain lines when every sampled line is a short value
+// without delimiters, and as fixed-width when every sampled line has the same
+// length of at least 20 characters. If none or more than one of these hold, a
+// *SniffError describing the evidence is returned.
+func SniffFormat(r io.Reader) (format FileFormat, br *bufio.Reader, err error) {
+	br = bufio.NewReaderSize(r, sniffSize)
+
+	sample, err := br.Peek(sniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return FileFormatUnknown, br, err
+	}
+	complete := err == io.EOF
+
+	format, err = sniffSample(sample, complete)
+	return format, br, err
+}
+
+// sniffSample determines the format of the provided sample. If the sample is
+// not complete, its final line is assumed to be truncated and is ignored.
+func sniffSample(sample []byte, complete bool) (format FileFormat, err error) {
+	sample = bytes.TrimPrefix(sample, utf8BOM)
+
+	var lines []string
+	for _, line := range strings.Split(string(sample), "\n") {
+		lines = append(lines, strings.TrimSuffix(line, "\r"))
+	}
+	if !complete && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+
+	var (
+		nonBlank    int
+		jsonLines   int
+		shortLines  int
+		commaCounts = make(map[int]int)
+		tabCounts   = make(map[int]int)
+		lengths     = make(map[int]int)
+		trimmed     string
+	)
+
+	// Gather observations from each non-blank line
+	for _, line := range lines {
+		trimmed = strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		nonBlank++
+
+		if strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") {
+			jsonLines++
+		}
+		if len(trimmed) <= maxLineValueLength && !strings.ContainsAny(trimmed, ",\t") {
+			shortLines++
+		}
+
+		commaCounts[strings.Count(line, ",")]++
+		tabCounts[strings.Count(line, "\t")]++
+		lengths[len(line)]++
+	}
+
+	if nonBlank == 0 {
+		return FileFormatUnknown, &SniffError{Evidence: "no non-blank lines in sample"}
+	}
+
+	var candidates []FileFormat
+	if jsonLines == nonBlank {
+		candidates = append(candidates, FileFormatJSONL)
+	}
+	if consistentDelimiter(commaCounts) {
+		candidates = append(candidates, FileFormatCSV)
+	}
+	if consistentDelimiter(tabCounts) {
+		candidates = append(candidates, FileFormatTSV)
+	}
+	if shortLines == nonBlank {
+		candidates = append(candidates, FileFormatLines)
+	}
+	if len(lengths) == 1 && commaCounts[0] == nonBlank && tabCounts[0] == nonBlank {
+		for length := range lengths {
+			if length >= minFixedWidthLength {
+				candidates = append(candidates, FileFormatFixedWidth)
+			}
+		}
+	}
+
+	// JSON objects commonly contain commas, so they take precedence over CSV
+	if len(candidates) == 2 && candidates[0] == FileFormatJSONL && candidates[1] == FileFormatCSV {
+		candidates = candidates[:1]
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	return FileFormatUnknown, &SniffError{
+		Candidates: candidates,
+		Evidence: fmt.Sprintf(
+			"%d lines sampled: %d JSON objects, %d short values, %d distinct comma counts, %d distinct tab counts, %d distinct line lengths",
+			nonBlank,
+			jsonLines,
+			shortLines,
+			len(commaCounts),
+			len(tabCounts),
+			len(lengths),
+		),
+	}
+}
+
+// consistentDelimiter determines whether every line contains the same non-zero
+// number of a delimiter, given the number of lines with each count.
+func consistentDelimiter(counts map[int]int) bool {
+	if len(counts) != 1 {
+		return false
+	}
+
+	_, none := counts[0]
+	return !none
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestSniffFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected FileFormat
+	}{
+		{"lines", "322286188\n021200025\r\n\n111000025\n", FileFormatLines},
+		{"lines with bom", "\xEF\xBB\xBF322286188\n021200025\n", FileFormatLines},
+		{"csv", "name,rtn\nAlice,322286188\nBob,021200025\n", FileFormatCSV},
+		{"tsv", "name\trtn\nAlice\t322286188\nBob\t021200025\n", FileFormatTSV},
+		{"jsonl", "{\"rtn\":\"322286188\",\"name\":\"a\"}\n{\"rtn\":\"021200025\"}\n", FileFormatJSONL},
+		{
+			"fixed width",
+			"322286188O0110000151A000000000ALPHA BANK\n" +
+				"021200025O0110000151A000000000BETA BANK \n",
+			FileFormatFixedWidth,
+		},
+		{
+			"truncated sample",
+			"322286188\n" + strings.Repeat("021200025\n", sniffSize/10) + "abcdefghijklmnopqrstuvwxyz",
+			FileFormatLines,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				format, br, err := SniffFormat(strings.NewReader(test.input))
+				if err != nil {
+					t.Fatalf("sniffing failed: %s", err)
+				}
+				if format != test.expected {
+					t.Fatalf("detected format \"%s\" (expected \"%s\")", format, test.expected)
+				}
+
+				// The returned reader must replay the entire input
+				replayed, err := ioutil.ReadAll(br)
+				if err != nil {
+					t.Fatalf("reading replayed input failed: %s", err)
+				}
+				if string(replayed) != test.input {
+					t.Fatalf("replayed input does not match original input")
+				}
+			},
+		)
+	}
+}
+
+func TestSniffFormatAmbiguous(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		candidates []FileFormat
+	}{
+		{"empty", "", nil},
+		{"blank", "\n\n  \n", nil},
+		{"mixed delimiters", "a,b\tc\nd,e\tf\n", []FileFormat{FileFormatCSV, FileFormatTSV}},
+		{"inconsistent", "a,b\nthis line has no delimiters and is long\n", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				format, _, err := SniffFormat(strings.NewReader(test.input))
+				if format != FileFormatUnknown || !errors.Is(err, ErrAmbiguousFormat) {
+					t.Fatalf("generated actual output \"%s\", \"%v\"", format, err)
+				}
+
+				var sniffErr *SniffError
+				if !errors.As(err, &sniffErr) {
+					t.Fatalf("error \"%v\" is not a *SniffError", err)
+				}
+				if sniffErr.Evidence == "" {
+					t.Fatalf("error carries no evidence")
+				}
+				if len(sniffErr.Candidates) != len(test.candidates) {
+					t.Fatalf(
+						"error carries candidates %v (expected %v)",
+						sniffErr.Candidates,
+						test.candidates,
+					)
+				}
+				for i := range test.candidates {
+					if sniffErr.Candidates[i] != test.candidates[i] {
+						t.Fatalf(
+							"error carries candidates %v (expected %v)",
+							sniffErr.Candidates,
+							test.candidates,
+						)
+					}
+				}
+			},
+		)
+	}
+}
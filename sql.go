@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+)
+
+// Scan implements the sql.Scanner interface. String and byte slice sources are
+// validated as-is, while integer sources are zero-padded to 9 digits before
+// validation, for compatibility with columns which have lost the leading zeros
+// of their RTNs. A NULL source produces the zero value. Invalid RTNs produce
+// the same errors as Parse.
+func (r *RTN) Scan(src interface{}) (err error) {
+	var s string
+
+	switch v := src.(type) {
+	case nil:
+		*r = RTN{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		s = padLeadingZeros(strconv.FormatInt(v, 10), 9)
+	default:
+		return fmt.Errorf("cannot scan %T into RTN", src)
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+// Value implements the driver.Valuer interface, producing the RTN in its
+// canonical 9-digit form. The zero value produces NULL.
+func (r RTN) Value() (driver.Value, error) {
+	if r.IsZero() {
+		return nil, nil
+	}
+
+	return r.value, nil
+}
+
+// NullRTN represents an RTN which may be NULL, analogous to sql.NullString.
+type NullRTN struct {
+	RTN   RTN
+	Valid bool // Valid is true if RTN is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullRTN) Scan(src interface{}) (err error) {
+	if src == nil {
+		n.RTN, n.Valid = RTN{}, false
+		return nil
+	}
+
+	if err = n.RTN.Scan(src); err != nil {
+		n.Valid = false
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullRTN) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	return n.RTN.Value()
+}
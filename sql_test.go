@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// Ensure the RTN types satisfy the database/sql interfaces.
+var (
+	_ sql.Scanner   = (*RTN)(nil)
+	_ driver.Valuer = RTN{}
+	_ sql.Scanner   = (*NullRTN)(nil)
+	_ driver.Valuer = NullRTN{}
+)
+
+func TestRTNScan(t *testing.T) {
+	tests := []struct {
+		input         interface{}
+		expectedRTN   string
+		expectedError error
+	}{
+		{"026014601", "026014601", nil},
+		{[]byte("322286188"), "322286188", nil},
+		{int64(26014601), "026014601", nil},
+		{int64(322286188), "322286188", nil},
+		{int64(0), "000000000", nil},
+		{nil, "", nil},
+		{"123456789", "", ErrChecksumMismatch},
+		{[]byte("12345678"), "", ErrIncorrectLength},
+		{int64(123456789), "", ErrChecksumMismatch},
+		{int64(1234567890), "", ErrIncorrectLength},
+		{int64(-26014601), "", ErrInvalidCharacter},
+		{"02601460O", "", ErrInvalidCharacter},
+	}
+
+	for _, test := range tests {
+		var rtn RTN
+		err := rtn.Scan(test.input)
+		if rtn.String() != test.expectedRTN || !errors.Is(err, test.expectedError) {
+			t.Fatalf(
+				"input \"%v\" generated actual output \"%s\", \"%v\" (expected \"%s\", \"%v\")",
+				test.input,
+				rtn,
+				err,
+				test.expectedRTN,
+				test.expectedError,
+			)
+		}
+	}
+
+	var rtn RTN
+	if err := rtn.Scan(26014601.0); err == nil {
+		t.Fatalf("scanning a float64 succeeded unexpectedly")
+	}
+}
+
+func TestRTNValue(t *testing.T) {
+	rtn, _ := Parse("026014601")
+
+	value, err := rtn.Value()
+	if err != nil || value != "026014601" {
+		t.Fatalf("valid RTN generated actual value \"%v\", \"%v\"", value, err)
+	}
+
+	value, err = RTN{}.Value()
+	if err != nil || value != nil {
+		t.Fatalf("zero value generated actual value \"%v\", \"%v\"", value, err)
+	}
+}
+
+func TestNullRTN(t *testing.T) {
+	var n NullRTN
+
+	if err := n.Scan(int64(26014601)); err != nil || !n.Valid || n.RTN.String() != "026014601" {
+		t.Fatalf("scanning an integer generated actual output %+v, \"%v\"", n, err)
+	}
+	if value, err := n.Value(); err != nil || value != "026014601" {
+		t.Fatalf("valid NullRTN generated actual value \"%v\", \"%v\"", value, err)
+	}
+
+	if err := n.Scan(nil); err != nil || n.Valid || !n.RTN.IsZero() {
+		t.Fatalf("scanning NULL generated actual output %+v, \"%v\"", n, err)
+	}
+	if value, err := n.Value(); err != nil || value != nil {
+		t.Fatalf("NULL NullRTN generated actual value \"%v\", \"%v\"", value, err)
+	}
+
+	if err := n.Scan("123456789"); !errors.Is(err, ErrChecksumMismatch) || n.Valid {
+		t.Fatalf("scanning an invalid RTN generated actual output %+v, \"%v\"", n, err)
+	}
+}
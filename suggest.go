@@ -0,0 +1,216 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"sort"
+)
+
+// CorrectionKind identifies the data-entry error which a Suggestion corrects.
+type CorrectionKind int
+
+const (
+	// CorrectionSubstitution corrects a single mistyped digit.
+	CorrectionSubstitution CorrectionKind = iota
+
+	// CorrectionTransposition corrects two swapped adjacent digits.
+	CorrectionTransposition
+)
+
+// String returns the name of the kind of correction.
+func (k CorrectionKind) String() string {
+	switch k {
+	case CorrectionSubstitution:
+		return "substitution"
+	case CorrectionTransposition:
+		return "transposition"
+	}
+
+	return "unknown"
+}
+
+// Suggestion is a valid RTN which may have been intended in place of an
+// invalid one.
+type Suggestion struct {
+	// RTN is the suggested RTN.
+	RTN string
+
+	// Kind is the data-entry error which the suggestion corrects.
+	Kind CorrectionKind
+
+	// Index is the position of the corrected digit, or of the first of the two
+	// swapped digits.
+	Index int
+}
+
+// SuggestCorrections returns every valid RTN which can be reached from the
+// provided RTN by changing exactly one digit or by swapping two adjacent
+// digits; these are the most common data-entry errors. Suggestions are
+// deduplicated and sorted. Since exactly one digit satisfies the checksum at
+// any position, an invalid RTN produces one substitution per position, along
+// with any transpositions which happen to be valid. An RTN which is already
+// valid produces an empty slice. The RTN must be 9 digits long.
+func SuggestCorrections(rtn string) (suggestions []string, err error) {
+	corrections, err := suggestCorrections(rtn)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions = make([]string, len(corrections))
+	for i, c := range corrections {
+		suggestions[i] = c.RTN
+	}
+
+	sort.Strings(suggestions)
+	return suggestions, nil
+}
+
+// SuggestCorrectionsRanked returns the same suggestions as SuggestCorrections,
+// describing each correction and ordering them from most to least likely.
+// Suggestions with assigned prefixes, as ValidateStrict accepts, come before
+// those with reserved prefixes. Within each, transpositions come first, since
+// the checksum rarely validates a transposition by chance, and ties are sorted
+// by RTN.
+func SuggestCorrectionsRanked(rtn string) (suggestions []Suggestion, err error) {
+	if suggestions, err = suggestCorrections(rtn); err != nil {
+		return nil, err
+	}
+
+	rank := func(s Suggestion) (r int) {
+		if classifyPrefix(prefixOf(s.RTN)) == ClassReserved {
+			r += 2
+		}
+		if s.Kind != CorrectionTransposition {
+			r++
+		}
+		return r
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if ri, rj := rank(suggestions[i]), rank(suggestions[j]); ri != rj {
+			return ri < rj
+		}
+		return suggestions[i].RTN < suggestions[j].RTN
+	})
+
+	return suggestions, nil
+}
+
+// suggestCorrections returns the unique corrections of the provided RTN in no
+// particular order.
+func suggestCorrections(rtn string) (suggestions []Suggestion, err error) {
+	if err = validateDigits(rtn); err != nil {
+		return nil, err
+	}
+
+	suggestions = []Suggestion{}
+	if isValid(rtn) {
+		return suggestions, nil
+	}
+
+	var (
+		candidate = []byte(rtn)
+		seen      = make(map[string]struct{})
+		i         int
+		c         byte
+	)
+
+	// consider records the candidate if it is valid and hasn't been seen
+	consider := func(kind CorrectionKind, index int) {
+		s := string(candidate)
+		if _, ok := seen[s]; ok || !isValid(s) {
+			return
+		}
+
+		seen[s] = struct{}{}
+		suggestions = append(suggestions, Suggestion{RTN: s, Kind: kind, Index: index})
+	}
+
+	// Change each digit to every other digit
+	for i = range candidate {
+		for c = '0'; c <= '9'; c++ {
+			if c == rtn[i] {
+				continue
+			}
+
+			candidate[i] = c
+			consider(CorrectionSubstitution, i)
+		}
+		candidate[i] = rtn[i]
+	}
+
+	// Swap each pair of adjacent, differing digits
+	for i = 0; i < len(candidate)-1; i++ {
+		if candidate[i] == candidate[i+1] {
+			continue
+		}
+
+		candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+		consider(CorrectionTransposition, i)
+		candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+	}
+
+	return suggestions, nil
+}
+
+// NearestValid returns every valid RTN which is exactly one edit from the
+// provided input: a single character substituted, inserted, or deleted.
+// Input of any characters is accepted, so "0212O0025" produces the RTNs with a
+// digit in place of the 'O', and input of 8 or 10 characters produces the RTNs
+// with a digit inserted or a character deleted. Results are deduplicated and
+// sorted, and exclude the input itself. Input which is not within one edit of
+// 9 characters produces an empty slice.
+func NearestValid(s string) (rtns []string) {
+	var (
+		runes = []rune(s)
+		seen  = make(map[string]struct{})
+		b     = make([]rune, 0, 10)
+		i     int
+		c     rune
+	)
+
+	rtns = []string{}
+
+	// consider records the candidate if it is valid and hasn't been seen
+	consider := func(candidate []rune) {
+		r := string(candidate)
+		if _, ok := seen[r]; ok || r == s || !isValid(r) {
+			return
+		}
+
+		seen[r] = struct{}{}
+		rtns = append(rtns, r)
+	}
+
+	switch len(runes) {
+	case 8:
+		// Insert every digit at each position
+		for i = 0; i <= len(runes); i++ {
+			for c = '0'; c <= '9'; c++ {
+				b = append(append(append(b[:0], runes[:i]...), c), runes[i:]...)
+				consider(b)
+			}
+		}
+	case 9:
+		// Change each character to every digit
+		b = append(b[:0], runes...)
+		for i = range runes {
+			for c = '0'; c <= '9'; c++ {
+				b[i] = c
+				consider(b)
+			}
+			b[i] = runes[i]
+		}
+	case 10:
+		// Delete each character
+		for i = range runes {
+			b = append(append(b[:0], runes[:i]...), runes[i+1:]...)
+			consider(b)
+		}
+	}
+
+	sort.Strings(rtns)
+	return rtns
+}
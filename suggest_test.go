@@ -0,0 +1,255 @@
+// Copyright (c) 2020 Matt Schultz <matt@schultz.is>. All rights reserved.
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package rtnutil
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSuggestCorrections(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		// Already valid
+		{"322286188", []string{}},
+
+		// Substitution of the final digit
+		{
+			"322286189",
+			[]string{
+				"321286189", "322256189", "322285189", "322286159", "322286188",
+				"322286489", "322586189", "392286189", "622286189",
+			},
+		},
+
+		// Transposition of the final two digits
+		{
+			"021200052",
+			[]string{
+				"021200012", "021200025", "021200054", "021200452", "021202052",
+				"021260052", "021600052", "023200052", "081200052", "201200052",
+				"421200052",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		suggestions, err := SuggestCorrections(test.input)
+		if err != nil {
+			t.Fatalf("input \"%s\" generated actual error \"%s\"", test.input, err)
+		}
+		if suggestions == nil {
+			t.Fatalf("input \"%s\" generated a nil slice", test.input)
+		}
+		if len(suggestions) != len(test.expected) {
+			t.Fatalf("input \"%s\" generated actual suggestions %v (expected %v)", test.input, suggestions, test.expected)
+		}
+		for i := range suggestions {
+			if suggestions[i] != test.expected[i] {
+				t.Fatalf("input \"%s\" generated actual suggestions %v (expected %v)", test.input, suggestions, test.expected)
+			}
+		}
+		if !sort.StringsAreSorted(suggestions) {
+			t.Fatalf("input \"%s\" generated unsorted suggestions", test.input)
+		}
+
+		seen := make(map[string]bool)
+		for _, suggestion := range suggestions {
+			if seen[suggestion] {
+				t.Fatalf("input \"%s\" generated duplicate suggestion \"%s\"", test.input, suggestion)
+			}
+			seen[suggestion] = true
+
+			if err = Validate(suggestion); err != nil {
+				t.Fatalf("input \"%s\" generated invalid suggestion \"%s\"", test.input, suggestion)
+			}
+		}
+	}
+}
+
+func TestSuggestCorrectionsIncludesOriginal(t *testing.T) {
+	originals := []string{"322286188", "021200025", "111000025", "026014601", "031100649"}
+
+	for _, original := range originals {
+		typos := []string{}
+
+		// Every single-digit substitution
+		for i := 0; i < 9; i++ {
+			for c := byte('0'); c <= '9'; c++ {
+				if c == original[i] {
+					continue
+				}
+				b := []byte(original)
+				b[i] = c
+				typos = append(typos, string(b))
+			}
+		}
+
+		// Every adjacent transposition which the checksum detects
+		for i := 0; i < 8; i++ {
+			b := []byte(original)
+			b[i], b[i+1] = b[i+1], b[i]
+			if Validate(string(b)) != nil {
+				typos = append(typos, string(b))
+			}
+		}
+
+		for _, typo := range typos {
+			suggestions, err := SuggestCorrections(typo)
+			if err != nil {
+				t.Fatalf("input \"%s\" generated actual error \"%s\"", typo, err)
+			}
+
+			i := sort.SearchStrings(suggestions, original)
+			if i == len(suggestions) || suggestions[i] != original {
+				t.Fatalf("input \"%s\" generated suggestions %v without \"%s\"", typo, suggestions, original)
+			}
+		}
+	}
+}
+
+func TestSuggestCorrectionsErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected error
+	}{
+		{"1234", ErrIncorrectLength},
+		{"0123456789", ErrIncorrectLength},
+		{"R22286188", ErrInvalidCharacter},
+		{"32228618X", ErrInvalidCharacter},
+	}
+
+	for _, test := range tests {
+		suggestions, err := SuggestCorrections(test.input)
+		if suggestions != nil || !errors.Is(err, test.expected) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %v, \"%v\" (expected \"%v\")",
+				test.input,
+				suggestions,
+				err,
+				test.expected,
+			)
+		}
+	}
+}
+
+func TestSuggestCorrectionsRanked(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Suggestion
+	}{
+		// Already valid
+		{"322286188", []Suggestion{}},
+
+		// Reserved prefixes are ranked last, each preceded by its transposition
+		{
+			"021200052",
+			[]Suggestion{
+				{"021200025", CorrectionTransposition, 7},
+				{"021200012", CorrectionSubstitution, 7},
+				{"021200054", CorrectionSubstitution, 8},
+				{"021200452", CorrectionSubstitution, 6},
+				{"021202052", CorrectionSubstitution, 5},
+				{"021260052", CorrectionSubstitution, 4},
+				{"021600052", CorrectionSubstitution, 3},
+				{"023200052", CorrectionSubstitution, 2},
+				{"081200052", CorrectionSubstitution, 1},
+				{"201200052", CorrectionTransposition, 0},
+				{"421200052", CorrectionSubstitution, 0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		suggestions, err := SuggestCorrectionsRanked(test.input)
+		if err != nil || !reflect.DeepEqual(suggestions, test.expected) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %v, \"%v\" (expected %v)",
+				test.input,
+				suggestions,
+				err,
+				test.expected,
+			)
+		}
+	}
+
+	if _, err := SuggestCorrectionsRanked("R22286188"); !errors.Is(err, ErrInvalidCharacter) {
+		t.Fatalf("generated actual error \"%v\" (expected \"%v\")", err, ErrInvalidCharacter)
+	}
+}
+
+func TestSuggestCorrectionsRankedMatchesSuggestCorrections(t *testing.T) {
+	for _, input := range []string{"322286189", "021200052", "111000052", "000000001"} {
+		ranked, _ := SuggestCorrectionsRanked(input)
+		suggestions, _ := SuggestCorrections(input)
+
+		rtns := make([]string, len(ranked))
+		for i, s := range ranked {
+			rtns[i] = s.RTN
+		}
+		sort.Strings(rtns)
+
+		if !reflect.DeepEqual(rtns, suggestions) {
+			t.Fatalf("input \"%s\" generated ranked suggestions %v (expected %v)", input, rtns, suggestions)
+		}
+	}
+}
+
+func TestNearestValid(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		// Substitutions of a valid RTN never produce another valid RTN
+		{"322286188", []string{}},
+
+		// Substitutions of an invalid RTN produce one RTN per position
+		{
+			"322286189",
+			[]string{
+				"321286189", "322256189", "322285189", "322286159", "322286188",
+				"322286489", "322586189", "392286189", "622286189",
+			},
+		},
+
+		// Only the invalid character can be substituted
+		{"0212O0025", []string{"021200025"}},
+		{"0212é0025", []string{"021200025"}},
+		{"0212OO025", []string{}},
+
+		// Insertions into 8 characters and deletions from 10
+		{
+			"32228618",
+			[]string{
+				"232228618", "322028618", "322248618", "322280618", "322286188",
+				"322286418", "326228618", "342228618",
+			},
+		},
+		{"3222861881", []string{"222861881", "322286188"}},
+		{"0212000255", []string{"021200025"}},
+		{"021-200025", []string{"021200025"}},
+
+		// Input more than one edit from 9 characters
+		{"", []string{}},
+		{"3222861", []string{}},
+		{"32228618811", []string{}},
+	}
+
+	for _, test := range tests {
+		actual := NearestValid(test.input)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf(
+				"input \"%s\" generated actual output %q (expected %q)",
+				test.input,
+				actual,
+				test.expected,
+			)
+		}
+	}
+}